@@ -0,0 +1,164 @@
+package apps
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ethAddress = common.HexToAddress("0xBe68fC2d8249eb60bfCf0e71D5A0d2F2e292c4eD")
+	token      = common.HexToAddress("0xEDa338E4dC46038493b885327842fD3E301CaB39")
+	accountID  = bytes.Repeat([]byte{0xd4}, 32)
+)
+
+// SCALE-encodes a 256-bit unsigned integer
+func encodeU256(value *big.Int) []byte {
+	encoded := make([]byte, 32)
+	bigEndian := value.Bytes()
+	for i, b := range bigEndian {
+		encoded[len(bigEndian)-1-i] = b
+	}
+	return encoded
+}
+
+func concat(items ...[]byte) []byte {
+	var result []byte
+	for _, item := range items {
+		result = append(result, item...)
+	}
+	return result
+}
+
+func TestDecodeEthereumPayloadETHApp(t *testing.T) {
+	amount := new(big.Int).Mul(big.NewInt(15), big.NewInt(1e17))
+	payload := concat([]byte{0x41, 0x01}, ethAddress.Bytes(), []byte{0}, accountID, encodeU256(amount))
+
+	transfer, err := DecodeEthereumPayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ETHApp, transfer.App)
+	assert.Equal(t, CallMint, transfer.Call)
+	assert.Equal(t, ethAddress.Bytes(), transfer.Sender)
+	assert.Equal(t, accountID, transfer.Recipient)
+	assert.Equal(t, amount, transfer.Amount)
+	assert.Contains(t, transfer.String(), "ETHApp.mint 1.5 ETH")
+}
+
+func TestDecodeEthereumPayloadERC20App(t *testing.T) {
+	amount := big.NewInt(1000)
+	payload := concat([]byte{0x42, 0x01}, token.Bytes(), ethAddress.Bytes(), []byte{0}, accountID, encodeU256(amount))
+
+	transfer, err := DecodeEthereumPayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ERC20App, transfer.App)
+	assert.Equal(t, token, *transfer.Token)
+	assert.Equal(t, ethAddress.Bytes(), transfer.Sender)
+	assert.Equal(t, amount, transfer.Amount)
+}
+
+func TestDecodeEthereumPayloadERC721App(t *testing.T) {
+	tokenID := big.NewInt(42)
+	payload := concat([]byte{0x43, 0x01}, ethAddress.Bytes(), []byte{0}, accountID, token.Bytes(), encodeU256(tokenID), []byte{0})
+
+	transfer, err := DecodeEthereumPayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ERC721App, transfer.App)
+	assert.Equal(t, token, *transfer.Token)
+	assert.Equal(t, tokenID, transfer.TokenID)
+	assert.Equal(t, accountID, transfer.Recipient)
+}
+
+func TestDecodeEthereumPayloadDOTApp(t *testing.T) {
+	amount := new(big.Int).Mul(big.NewInt(10000), big.NewInt(1e18))
+	payload := concat([]byte{0x40, 0x01}, ethAddress.Bytes(), []byte{0}, accountID, encodeU256(amount))
+
+	transfer, err := DecodeEthereumPayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, DOTApp, transfer.App)
+	assert.Equal(t, CallUnlock, transfer.Call)
+	assert.Contains(t, transfer.String(), "DOTApp.unlock 10000 DOT")
+}
+
+func TestDecodeEthereumPayloadInvalid(t *testing.T) {
+	_, err := DecodeEthereumPayload([]byte{0x99, 0x01, 0x00})
+	assert.Equal(t, ErrUnknownPayload, err)
+
+	// Truncated amount
+	payload := concat([]byte{0x41, 0x01}, ethAddress.Bytes(), []byte{0}, accountID, make([]byte, 16))
+	_, err = DecodeEthereumPayload(payload)
+	assert.Error(t, err)
+
+	// Trailing bytes
+	payload = concat([]byte{0x41, 0x01}, ethAddress.Bytes(), []byte{0}, accountID, make([]byte, 33))
+	_, err = DecodeEthereumPayload(payload)
+	assert.Error(t, err)
+}
+
+func encodeCall(t *testing.T, signature string, types []string, values ...interface{}) []byte {
+	encoded, err := Arguments(types...).Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(crypto.Keccak256([]byte(signature))[:4], encoded...)
+}
+
+func TestDecodeParachainPayload(t *testing.T) {
+	var sender [32]byte
+	copy(sender[:], accountID)
+	amount := big.NewInt(1e18)
+
+	transfer, err := DecodeParachainPayload(encodeCall(t, "unlock(bytes32,address,uint256)",
+		[]string{"bytes32", "address", "uint256"}, sender, ethAddress, amount))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ETHApp, transfer.App)
+	assert.Equal(t, CallUnlock, transfer.Call)
+	assert.Equal(t, accountID, transfer.Sender)
+	assert.Equal(t, ethAddress.Bytes(), transfer.Recipient)
+	assert.Equal(t, amount, transfer.Amount)
+
+	transfer, err = DecodeParachainPayload(encodeCall(t, "unlock(address,bytes32,address,uint256)",
+		[]string{"address", "bytes32", "address", "uint256"}, token, sender, ethAddress, amount))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ERC20App, transfer.App)
+	assert.Equal(t, token, *transfer.Token)
+
+	transfer, err = DecodeParachainPayload(encodeCall(t, "unlock(address,uint256,bytes32,address)",
+		[]string{"address", "uint256", "bytes32", "address"}, token, big.NewInt(7), sender, ethAddress))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ERC721App, transfer.App)
+	assert.Equal(t, big.NewInt(7), transfer.TokenID)
+
+	transfer, err = DecodeParachainPayload(encodeCall(t, "mint(bytes32,address,uint256)",
+		[]string{"bytes32", "address", "uint256"}, sender, ethAddress, amount))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, DOTApp, transfer.App)
+	assert.Equal(t, CallMint, transfer.Call)
+	assert.Contains(t, transfer.String(), "DOTApp.mint 1 DOT")
+
+	_, err = DecodeParachainPayload([]byte{1, 2, 3, 4})
+	assert.Equal(t, ErrUnknownPayload, err)
+}
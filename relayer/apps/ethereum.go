@@ -0,0 +1,139 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package apps
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Calls dispatched on the parachain are identified by their pallet and call indices
+var ethereumPayloadDecoders = map[[2]byte]func(*payloadReader) (*Transfer, error){
+	{0x41, 0x01}: decodeETHAppMint,
+	{0x42, 0x01}: decodeERC20AppMint,
+	{0x43, 0x01}: decodeERC721AppMint,
+	{0x40, 0x01}: decodeDOTAppUnlock,
+}
+
+// DecodeEthereumPayload decodes the payload of a message sent by an application on Ethereum, which is
+// a SCALE-encoded call of the application's pallet. ErrUnknownPayload is returned for other payloads.
+func DecodeEthereumPayload(payload []byte) (*Transfer, error) {
+	if len(payload) < 2 {
+		return nil, ErrUnknownPayload
+	}
+
+	decode, ok := ethereumPayloadDecoders[[2]byte{payload[0], payload[1]}]
+	if !ok {
+		return nil, ErrUnknownPayload
+	}
+
+	reader := &payloadReader{data: payload[2:]}
+	transfer, err := decode(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if reader.err != nil {
+		return nil, reader.err
+	}
+	if len(reader.data) != 0 {
+		return nil, fmt.Errorf("%d unexpected trailing bytes in %s payload", len(reader.data), transfer.App)
+	}
+
+	return transfer, nil
+}
+
+// ETHApp.lock: mint(sender: H160, recipient: MultiAddress, amount: U256)
+func decodeETHAppMint(r *payloadReader) (*Transfer, error) {
+	return &Transfer{
+		App:       ETHApp,
+		Call:      CallMint,
+		Sender:    r.bytes(20),
+		Recipient: r.multiAddressID(),
+		Amount:    r.u256(),
+	}, nil
+}
+
+// ERC20App.lock: mint(token: H160, sender: H160, recipient: MultiAddress, amount: U256)
+func decodeERC20AppMint(r *payloadReader) (*Transfer, error) {
+	token := common.BytesToAddress(r.bytes(20))
+	return &Transfer{
+		App:       ERC20App,
+		Call:      CallMint,
+		Token:     &token,
+		Sender:    r.bytes(20),
+		Recipient: r.multiAddressID(),
+		Amount:    r.u256(),
+	}, nil
+}
+
+// ERC721App.lock: mint(sender: H160, recipient: MultiAddress, token_contract: H160, token_id: U256, token_uri: Vec<u8>)
+func decodeERC721AppMint(r *payloadReader) (*Transfer, error) {
+	transfer := &Transfer{
+		App:       ERC721App,
+		Call:      CallMint,
+		Sender:    r.bytes(20),
+		Recipient: r.multiAddressID(),
+	}
+	token := common.BytesToAddress(r.bytes(20))
+	transfer.Token = &token
+	transfer.TokenID = r.u256()
+	// The token URI is always empty
+	if !bytes.Equal(r.bytes(1), []byte{0}) && r.err == nil {
+		return nil, fmt.Errorf("unexpected token URI in %s payload", ERC721App)
+	}
+	return transfer, nil
+}
+
+// DOTApp.burn: unlock(sender: H160, recipient: MultiAddress, amount: U256)
+func decodeDOTAppUnlock(r *payloadReader) (*Transfer, error) {
+	return &Transfer{
+		App:       DOTApp,
+		Call:      CallUnlock,
+		Sender:    r.bytes(20),
+		Recipient: r.multiAddressID(),
+		Amount:    r.u256(),
+	}, nil
+}
+
+// payloadReader reads SCALE-encoded fields, recording the first error encountered
+type payloadReader struct {
+	data []byte
+	err  error
+}
+
+func (r *payloadReader) bytes(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.data) < n {
+		r.err = fmt.Errorf("payload too short")
+		return make([]byte, n)
+	}
+	value := r.data[:n]
+	r.data = r.data[n:]
+	return value
+}
+
+// Reads a MultiAddress, which the applications always encode as the Id variant
+func (r *payloadReader) multiAddressID() []byte {
+	variant := r.bytes(1)
+	if variant[0] != 0 && r.err == nil {
+		r.err = fmt.Errorf("unsupported MultiAddress variant %d", variant[0])
+	}
+	return r.bytes(32)
+}
+
+// Reads a little-endian 256-bit unsigned integer
+func (r *payloadReader) u256() *big.Int {
+	encoded := r.bytes(32)
+	bigEndian := make([]byte, len(encoded))
+	for i, b := range encoded {
+		bigEndian[len(encoded)-1-i] = b
+	}
+	return new(big.Int).SetBytes(bigEndian)
+}
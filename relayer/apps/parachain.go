@@ -0,0 +1,121 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package apps
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type parachainPayloadDecoder struct {
+	app       string
+	call      string
+	arguments abi.Arguments
+	decode    func(transfer *Transfer, values []interface{})
+}
+
+var parachainPayloadDecoders = map[[4]byte]*parachainPayloadDecoder{}
+
+func registerParachainPayloadDecoder(signature string, decoder *parachainPayloadDecoder) {
+	var selector [4]byte
+	copy(selector[:], crypto.Keccak256([]byte(signature))[:4])
+	parachainPayloadDecoders[selector] = decoder
+}
+
+func init() {
+	// Payloads are ABI-encoded calls of the applications on Ethereum
+	registerParachainPayloadDecoder("unlock(bytes32,address,uint256)", &parachainPayloadDecoder{
+		app:       ETHApp,
+		call:      CallUnlock,
+		arguments: Arguments("bytes32", "address", "uint256"),
+		decode: func(transfer *Transfer, values []interface{}) {
+			transfer.Sender = bytes32(values[0])
+			transfer.Recipient = values[1].(common.Address).Bytes()
+			transfer.Amount = values[2].(*big.Int)
+		},
+	})
+	registerParachainPayloadDecoder("unlock(address,bytes32,address,uint256)", &parachainPayloadDecoder{
+		app:       ERC20App,
+		call:      CallUnlock,
+		arguments: Arguments("address", "bytes32", "address", "uint256"),
+		decode: func(transfer *Transfer, values []interface{}) {
+			token := values[0].(common.Address)
+			transfer.Token = &token
+			transfer.Sender = bytes32(values[1])
+			transfer.Recipient = values[2].(common.Address).Bytes()
+			transfer.Amount = values[3].(*big.Int)
+		},
+	})
+	registerParachainPayloadDecoder("unlock(address,uint256,bytes32,address)", &parachainPayloadDecoder{
+		app:       ERC721App,
+		call:      CallUnlock,
+		arguments: Arguments("address", "uint256", "bytes32", "address"),
+		decode: func(transfer *Transfer, values []interface{}) {
+			token := values[0].(common.Address)
+			transfer.Token = &token
+			transfer.TokenID = values[1].(*big.Int)
+			transfer.Sender = bytes32(values[2])
+			transfer.Recipient = values[3].(common.Address).Bytes()
+		},
+	})
+	registerParachainPayloadDecoder("mint(bytes32,address,uint256)", &parachainPayloadDecoder{
+		app:       DOTApp,
+		call:      CallMint,
+		arguments: Arguments("bytes32", "address", "uint256"),
+		decode: func(transfer *Transfer, values []interface{}) {
+			transfer.Sender = bytes32(values[0])
+			transfer.Recipient = values[1].(common.Address).Bytes()
+			transfer.Amount = values[2].(*big.Int)
+		},
+	})
+}
+
+// DecodeParachainPayload decodes the payload of a message sent by a pallet on the parachain, which is
+// an ABI-encoded call of the matching application on Ethereum. ErrUnknownPayload is returned for other payloads.
+func DecodeParachainPayload(payload []byte) (*Transfer, error) {
+	if len(payload) < 4 {
+		return nil, ErrUnknownPayload
+	}
+
+	var selector [4]byte
+	copy(selector[:], payload[:4])
+	decoder, ok := parachainPayloadDecoders[selector]
+	if !ok {
+		return nil, ErrUnknownPayload
+	}
+
+	values, err := decoder.arguments.Unpack(payload[4:])
+	if err != nil {
+		return nil, fmt.Errorf("decode %s.%s payload: %w", decoder.app, decoder.call, err)
+	}
+
+	transfer := &Transfer{
+		App:  decoder.app,
+		Call: decoder.call,
+	}
+	decoder.decode(transfer, values)
+	return transfer, nil
+}
+
+// Arguments returns ABI arguments of the given types, which must be valid
+func Arguments(types ...string) abi.Arguments {
+	var args abi.Arguments
+	for _, name := range types {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		args = append(args, abi.Argument{Type: typ})
+	}
+	return args
+}
+
+func bytes32(value interface{}) []byte {
+	b := value.([32]byte)
+	return b[:]
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package apps decodes the payloads of messages sent between the bridge applications on Ethereum
+// (ETHApp, ERC20App, ERC721App and DOTApp) and their pallets on the parachain.
+package apps
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	ETHApp    = "ETHApp"
+	ERC20App  = "ERC20App"
+	ERC721App = "ERC721App"
+	DOTApp    = "DOTApp"
+)
+
+const (
+	// Mints wrapped assets for assets locked on the other chain
+	CallMint = "mint"
+	// Releases assets for wrapped assets burned on the other chain
+	CallUnlock = "unlock"
+)
+
+// Both ETH and wrapped DOT on Ethereum have 18 decimals
+const etherDecimals = 18
+
+var ErrUnknownPayload = errors.New("unknown payload format")
+
+// Transfer is the decoded payload of a message sent by a bridge application
+type Transfer struct {
+	App  string
+	Call string
+	// Sender and recipient are Ethereum addresses or Substrate account IDs, depending on the direction
+	Sender    []byte
+	Recipient []byte
+	// Token contract on Ethereum, for ERC20 and ERC721 transfers
+	Token *common.Address
+	// Amount transferred, for fungible assets
+	Amount *big.Int
+	// ID of the transferred token, for ERC721 transfers
+	TokenID *big.Int
+}
+
+// String describes the transfer in a human-readable form
+func (t *Transfer) String() string {
+	var asset string
+	switch t.App {
+	case ETHApp:
+		asset = formatUnits(t.Amount, etherDecimals) + " ETH"
+	case DOTApp:
+		asset = formatUnits(t.Amount, etherDecimals) + " DOT"
+	case ERC20App:
+		asset = fmt.Sprintf("%s of token %s", t.Amount.String(), t.Token.Hex())
+	case ERC721App:
+		asset = fmt.Sprintf("token %s of %s", t.TokenID.String(), t.Token.Hex())
+	}
+
+	return fmt.Sprintf("%s.%s %s from %s to %s",
+		t.App, t.Call, asset, hexutil.Encode(t.Sender), hexutil.Encode(t.Recipient))
+}
+
+// Formats an amount with the given number of decimals, e.g. 1500000000000000000 as 1.5
+func formatUnits(amount *big.Int, decimals int64) string {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)
+	value := new(big.Rat).SetFrac(amount, unit).FloatString(int(decimals))
+	value = strings.TrimRight(value, "0")
+	return strings.TrimSuffix(value, ".")
+}
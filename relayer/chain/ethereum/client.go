@@ -0,0 +1,258 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/metrics"
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+// Client retries the requests made through an Ethereum client which fail with transient errors, and
+// records each attempt in the RPC metrics. Transactions are only sent once, as a failed send may still
+// have reached the node, and subscriptions aren't retried, as their callers already resubscribe when
+// they fail. A failed subscription marks the connection as no longer connected.
+type Client struct {
+	*ethclient.Client
+	endpoint  string
+	policy    retry.Policy
+	connected *health.Condition
+}
+
+func newClient(client *ethclient.Client, endpoint string, policy retry.Policy, connected *health.Condition) *Client {
+	return &Client{Client: client, endpoint: endpoint, policy: policy, connected: connected}
+}
+
+func (c *Client) do(ctx context.Context, method string, fn func() error) error {
+	return c.policy.DoRequest(ctx, c.endpoint, method, func() error {
+		return c.observe(method, fn)
+	})
+}
+
+func (c *Client) observe(method string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	metrics.ObserveRPC(c.endpoint, method, start, err)
+	return err
+}
+
+func (c *Client) ChainID(ctx context.Context) (result *big.Int, err error) {
+	err = c.do(ctx, "eth_chainId", func() error {
+		result, err = c.Client.ChainID(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) NetworkID(ctx context.Context) (result *big.Int, err error) {
+	err = c.do(ctx, "net_version", func() error {
+		result, err = c.Client.NetworkID(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (result uint64, err error) {
+	err = c.do(ctx, "eth_blockNumber", func() error {
+		result, err = c.Client.BlockNumber(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) BlockByHash(ctx context.Context, hash common.Hash) (result *types.Block, err error) {
+	err = c.do(ctx, "eth_getBlockByHash", func() error {
+		result, err = c.Client.BlockByHash(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (result *types.Block, err error) {
+	err = c.do(ctx, "eth_getBlockByNumber", func() error {
+		result, err = c.Client.BlockByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (result *types.Header, err error) {
+	err = c.do(ctx, "eth_getBlockByHash", func() error {
+		result, err = c.Client.HeaderByHash(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (result *types.Header, err error) {
+	err = c.do(ctx, "eth_getBlockByNumber", func() error {
+		result, err = c.Client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = c.do(ctx, "eth_getTransactionByHash", func() error {
+		tx, isPending, err = c.Client.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (result *types.Receipt, err error) {
+	err = c.do(ctx, "eth_getTransactionReceipt", func() error {
+		result, err = c.Client.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (result []byte, err error) {
+	err = c.do(ctx, "eth_getCode", func() error {
+		result, err = c.Client.CodeAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) (result []byte, err error) {
+	err = c.do(ctx, "eth_getCode", func() error {
+		result, err = c.Client.PendingCodeAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (result uint64, err error) {
+	err = c.do(ctx, "eth_getTransactionCount", func() error {
+		result, err = c.Client.NonceAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (result uint64, err error) {
+	err = c.do(ctx, "eth_getTransactionCount", func() error {
+		result, err = c.Client.PendingNonceAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (result *big.Int, err error) {
+	err = c.do(ctx, "eth_getBalance", func() error {
+		result, err = c.Client.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (result []types.Log, err error) {
+	err = c.do(ctx, "eth_getLogs", func() error {
+		result, err = c.Client.FilterLogs(ctx, q)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = c.do(ctx, "eth_call", func() error {
+		result, err = c.Client.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) (result []byte, err error) {
+	err = c.do(ctx, "eth_call", func() error {
+		result, err = c.Client.PendingCallContract(ctx, msg)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (result *big.Int, err error) {
+	err = c.do(ctx, "eth_gasPrice", func() error {
+		result, err = c.Client.SuggestGasPrice(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) SuggestGasTipCap(ctx context.Context) (result *big.Int, err error) {
+	err = c.do(ctx, "eth_maxPriorityFeePerGas", func() error {
+		result, err = c.Client.SuggestGasTipCap(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (result uint64, err error) {
+	err = c.do(ctx, "eth_estimateGas", func() error {
+		result, err = c.Client.EstimateGas(ctx, msg)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.observe("eth_sendRawTransaction", func() error {
+		return c.Client.SendTransaction(ctx, tx)
+	})
+}
+
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (result ethereum.Subscription, err error) {
+	err = c.observe("eth_subscribe", func() error {
+		result, err = c.Client.SubscribeNewHead(ctx, ch)
+		return err
+	})
+	return c.monitor(result, err)
+}
+
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (result ethereum.Subscription, err error) {
+	err = c.observe("eth_subscribe", func() error {
+		result, err = c.Client.SubscribeFilterLogs(ctx, q, ch)
+		return err
+	})
+	return c.monitor(result, err)
+}
+
+func (c *Client) monitor(sub ethereum.Subscription, err error) (ethereum.Subscription, error) {
+	if err != nil {
+		c.connected.Unsatisfy()
+		return nil, err
+	}
+
+	monitored := &monitoredSubscription{Subscription: sub, err: make(chan error, 1)}
+	go func() {
+		defer close(monitored.err)
+		for err := range sub.Err() {
+			if err != nil {
+				c.connected.Unsatisfy()
+			}
+			monitored.err <- err
+		}
+	}()
+	return monitored, nil
+}
+
+// monitoredSubscription passes on the errors of a subscription after they are recorded
+type monitoredSubscription struct {
+	ethereum.Subscription
+	err chan error
+}
+
+func (s *monitoredSubscription) Err() <-chan error {
+	return s.err
+}
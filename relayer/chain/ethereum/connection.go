@@ -7,36 +7,44 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/sirupsen/logrus"
 
-	"github.com/snowfork/snowbridge/relayer/crypto/secp256k1"
+	"github.com/snowfork/snowbridge/relayer/crypto"
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/retry"
 
 	log "github.com/sirupsen/logrus"
 )
 
 type Connection struct {
-	endpoint string
-	kp       *secp256k1.Keypair
-	client   *ethclient.Client
-	chainID  *big.Int
+	endpoint  string
+	signer    crypto.Signer
+	client    *Client
+	chainID   *big.Int
+	connected *health.Condition
 }
 
-func NewConnection(endpoint string, kp *secp256k1.Keypair) *Connection {
+// NewConnection creates a connection to the Ethereum node. The signer is only needed to send transactions.
+func NewConnection(endpoint string, signer crypto.Signer) *Connection {
 	return &Connection{
-		endpoint: endpoint,
-		kp:       kp,
+		endpoint:  endpoint,
+		signer:    signer,
+		connected: health.Expect("ethereum connection to " + endpoint),
 	}
 }
 
 func (co *Connection) Connect(ctx context.Context) error {
-	client, err := ethclient.Dial(co.endpoint)
+	dialed, err := ethclient.Dial(co.endpoint)
 	if err != nil {
 		return err
 	}
+	client := newClient(dialed, co.endpoint, retry.DefaultPolicy, co.connected)
 
 	chainID, err := client.NetworkID(ctx)
 	if err != nil {
+		dialed.Close()
 		return err
 	}
 
@@ -45,26 +53,48 @@ func (co *Connection) Connect(ctx context.Context) error {
 		"chainID":  chainID,
 	}).Info("Connected to chain")
 
+	// Replace the client of a connection which was lost
+	if co.client != nil {
+		co.client.Close()
+	}
 	co.client = client
 	co.chainID = chainID
+	co.connected.Satisfy()
 
 	return nil
 }
 
+// Close closes the connection, which then no longer counts towards readiness
 func (co *Connection) Close() {
 	if co.client != nil {
 		co.client.Close()
 	}
+	co.connected.Remove()
+}
+
+// IsConnected returns whether the connection was established and hasn't been lost since
+func (co *Connection) IsConnected() bool {
+	return co.connected.Satisfied()
 }
 
-func (co *Connection) GetClient() *ethclient.Client {
+// GetClient returns the client, which retries requests failing with transient errors
+func (co *Connection) GetClient() *Client {
 	return co.client
 }
 
-func (co *Connection) GetKP() *secp256k1.Keypair {
-	return co.kp
+func (co *Connection) Signer() crypto.Signer {
+	return co.signer
 }
 
 func (co *Connection) ChainID() *big.Int {
 	return co.chainID
 }
+
+// TransactionGasUsed returns the gas used by the mined transaction with the given hash
+func (co *Connection) TransactionGasUsed(ctx context.Context, txHash common.Hash) (uint64, error) {
+	receipt, err := co.client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return 0, err
+	}
+	return receipt.GasUsed, nil
+}
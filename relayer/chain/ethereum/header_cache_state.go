@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"time"
 
 	gethCommon "github.com/ethereum/go-ethereum/common"
 	gethTypes "github.com/ethereum/go-ethereum/core/types"
 	gethTrie "github.com/ethereum/go-ethereum/trie"
 	"github.com/snowfork/ethashproof"
 	"golang.org/x/sync/errgroup"
+
+	"github.com/snowfork/snowbridge/relayer/retry"
 )
 
 type BlockLoader interface {
@@ -109,10 +112,12 @@ func (d *DefaultCacheLoader) MakeCache(epoch uint64) (*ethashproof.DatasetMerkle
 	cache, err := ethashproof.LoadCache(int(epoch), d.CacheDir)
 	if err != nil {
 		// Cache probably doesn't exist - create it
+		start := time.Now()
 		_, err := ethashproof.CalculateDatasetMerkleRoot(epoch, true, d.DataDir, d.CacheDir)
 		if err != nil {
 			return nil, err
 		}
+		dagGenerationDuration.Observe(time.Since(start).Seconds())
 
 		return ethashproof.LoadCache(int(epoch), d.CacheDir)
 	}
@@ -211,7 +216,7 @@ func (s *HeaderCache) GetReceiptTrie(ctx context.Context, hash gethCommon.Hash)
 	}
 
 	if receiptTrie.Hash() != block.ReceiptHash() {
-		return nil, fmt.Errorf("receipt trie does not match block receipt hash")
+		return nil, retry.Fatal(fmt.Errorf("receipt trie does not match block receipt hash"))
 	}
 
 	s.blockCache.Insert(block, receiptTrie)
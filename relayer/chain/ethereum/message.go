@@ -6,6 +6,7 @@ package ethereum
 import (
 	"bytes"
 	"encoding/hex"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	etypes "github.com/ethereum/go-ethereum/core/types"
@@ -13,6 +14,7 @@ import (
 	etrie "github.com/ethereum/go-ethereum/trie"
 	"github.com/sirupsen/logrus"
 	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+	"github.com/snowfork/snowbridge/relayer/apps"
 	"github.com/snowfork/snowbridge/relayer/chain"
 	"github.com/snowfork/snowbridge/relayer/chain/parachain"
 
@@ -47,21 +49,26 @@ func MakeMessageFromEvent(mapping map[common.Address]string, event *etypes.Log,
 		},
 	}
 
+	call, ok := mapping[event.Address]
+	if !ok {
+		return nil, err
+	}
+
 	value := hex.EncodeToString(m.Data)
-	log.WithFields(logrus.Fields{
+	fields := logrus.Fields{
 		"payload":    value,
 		"blockHash":  m.Proof.BlockHash.Hex(),
 		"eventIndex": m.Proof.TxIndex,
-	}).Debug("Generated message from Ethereum log")
+	}
+	transfer, err := DecodeTransferFromEvent(call, event)
+	if err == nil {
+		fields["transfer"] = transfer.String()
+	}
+	log.WithFields(fields).Debug("Generated message from Ethereum log")
 
 	var args []interface{}
 	args = append(args, m)
 
-	call, ok := mapping[event.Address]
-	if !ok {
-		return nil, err
-	}
-
 	message := chain.EthereumOutboundMessage{
 		Call: call,
 		Args: args,
@@ -69,3 +76,30 @@ func MakeMessageFromEvent(mapping map[common.Address]string, event *etypes.Log,
 
 	return &message, nil
 }
+
+// Non-indexed fields of the Message events emitted by the outbound channels
+var (
+	basicMessageEventArgs        = apps.Arguments("address", "uint64", "bytes")
+	incentivizedMessageEventArgs = apps.Arguments("address", "uint64", "uint256", "bytes")
+)
+
+// DecodeTransferFromEvent decodes the transfer described by the payload of a Message event. The call which
+// submits the message on the parachain identifies the channel which emitted the event.
+func DecodeTransferFromEvent(call string, event *etypes.Log) (*apps.Transfer, error) {
+	args := basicMessageEventArgs
+	if strings.HasPrefix(call, "IncentivizedInboundChannel") {
+		args = incentivizedMessageEventArgs
+	}
+
+	values, err := args.Unpack(event.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, ok := values[len(values)-1].([]byte)
+	if !ok {
+		return nil, apps.ErrUnknownPayload
+	}
+
+	return apps.DecodeEthereumPayload(payload)
+}
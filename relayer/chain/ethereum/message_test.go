@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	gethTrie "github.com/ethereum/go-ethereum/trie"
+	"github.com/snowfork/snowbridge/relayer/apps"
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
 	"github.com/snowfork/snowbridge/relayer/chain/parachain"
 	"github.com/stretchr/testify/assert"
@@ -78,3 +80,25 @@ func TestMessage_Proof(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, provenReceipt, receipt5Encoded)
 }
+
+func TestDecodeTransferFromEvent(t *testing.T) {
+	args := apps.Arguments("address", "uint64", "bytes")
+
+	// ETHApp mint of 1 ETH
+	amount := make([]byte, 32)
+	copy(amount, []byte{0x00, 0x00, 0x64, 0xa7, 0xb3, 0xb6, 0xe0, 0x0d})
+	payload := append([]byte{0x41, 0x01}, common.HexToAddress("0x01").Bytes()...)
+	payload = append(payload, 0)
+	payload = append(payload, make([]byte, 32)...)
+	payload = append(payload, amount...)
+
+	data, err := args.Pack(common.HexToAddress("0x02"), uint64(1), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfer, err := ethereum.DecodeTransferFromEvent("BasicInboundChannel.submit", &gethTypes.Log{Data: data})
+	assert.Nil(t, err)
+	assert.Equal(t, "ETHApp", transfer.App)
+	assert.Contains(t, transfer.String(), "1 ETH")
+}
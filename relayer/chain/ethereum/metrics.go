@@ -0,0 +1,19 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	dagGenerationDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "snowbridge",
+		Subsystem: "ethash",
+		Name:      "dag_generation_duration_seconds",
+		Help:      "Time taken to generate the ethash DAG and merkle cache for an epoch.",
+		Buckets:   prometheus.ExponentialBuckets(30, 2, 8),
+	})
+)
@@ -0,0 +1,209 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	geth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type RevertClass int
+
+const (
+	// The revert reason is not known to the relayer
+	RevertUnknown RevertClass = iota
+	// The proof was generated against an MMR root which the light client no longer holds
+	RevertStaleRoot
+	// The message or commitment has already been accepted on Ethereum
+	RevertNonceUsed
+	// The proof or signatures are invalid and will never be accepted
+	RevertInvalidProof
+	// The message is ahead of the nonce expected by the inbound channel, as earlier messages
+	// have not been delivered
+	RevertNonceGap
+)
+
+func (c RevertClass) String() string {
+	switch c {
+	case RevertStaleRoot:
+		return "StaleRoot"
+	case RevertNonceUsed:
+		return "NonceUsed"
+	case RevertInvalidProof:
+		return "InvalidProof"
+	case RevertNonceGap:
+		return "NonceGap"
+	default:
+		return "Unknown"
+	}
+}
+
+// Revert reasons emitted by the bridge contracts
+var revertClasses = map[string]RevertClass{
+	// ParachainLightClient
+	"Invalid proof": RevertStaleRoot,
+	// BasicInboundChannel & IncentivizedInboundChannel. Also emitted for nonces ahead of the
+	// channel, see ClassifyNonceRevert.
+	"invalid nonce": RevertNonceUsed,
+	// BeefyLightClient
+	"Payload blocknumber is too old":                                 RevertNonceUsed,
+	"Payload blocknumber is too new":                                 RevertInvalidProof,
+	"Error: Invalid Signature":                                       RevertInvalidProof,
+	"Error: Sender must be in validator set at correct position":     RevertInvalidProof,
+	"Error: Bitfield not enough validators":                          RevertInvalidProof,
+	"Error: Sender address does not match original validation data":  RevertInvalidProof,
+	"Error: Number of signatures does not match required":            RevertInvalidProof,
+	"Error: Number of validator positions does not match required":   RevertInvalidProof,
+	"Error: Number of validator public keys does not match required": RevertInvalidProof,
+	"Error: Validator must be once in bitfield":                      RevertInvalidProof,
+}
+
+func ClassifyRevert(reason string) RevertClass {
+	class, ok := revertClasses[reason]
+	if !ok {
+		return RevertUnknown
+	}
+	return class
+}
+
+// ClassifyNonceRevert tells apart the two causes of the inbound channels' "invalid nonce" revert.
+// The messages have already been delivered if the first nonce is at or below the nonce of the
+// last message accepted by the channel. Otherwise earlier messages are missing.
+func ClassifyNonceRevert(firstNonce, acceptedNonce uint64) RevertClass {
+	if firstNonce <= acceptedNonce {
+		return RevertNonceUsed
+	}
+	return RevertNonceGap
+}
+
+// RevertError is returned when a simulated transaction reverts
+type RevertError struct {
+	Method string
+	Reason string
+	Class  RevertClass
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("simulation of %s reverted (%s): %s", e.Method, e.Class, e.Reason)
+}
+
+// DecodeRevertReason extracts the revert reason from an error returned by eth_call.
+// Returns false if the error does not describe a reverted execution.
+func DecodeRevertReason(err error) (string, bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			raw, decodeErr := hexutil.Decode(data)
+			if decodeErr == nil {
+				reason, unpackErr := abi.UnpackRevert(raw)
+				if unpackErr == nil {
+					return reason, true
+				}
+			}
+		}
+	}
+
+	// Nodes which don't return the revert data (e.g. ganache) include the reason in the message
+	message := err.Error()
+	for _, prefix := range []string{"execution reverted: ", "VM Exception while processing transaction: revert "} {
+		if i := strings.Index(message, prefix); i >= 0 {
+			return message[i+len(prefix):], true
+		}
+	}
+	for _, suffix := range []string{"execution reverted", "VM Exception while processing transaction: revert"} {
+		if strings.HasSuffix(message, suffix) {
+			return "", true
+		}
+	}
+
+	return "", false
+}
+
+// Simulator executes contract calls with eth_call against the pending state, so that
+// transactions which would revert can be detected before they are sent.
+type Simulator struct {
+	conn    *Connection
+	address common.Address
+	abi     abi.ABI
+}
+
+func NewSimulator(conn *Connection, address common.Address, contractABI string) (*Simulator, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Simulator{
+		conn:    conn,
+		address: address,
+		abi:     parsed,
+	}, nil
+}
+
+// Simulate calls the given contract method. A *RevertError is returned if the call reverts.
+func (s *Simulator) Simulate(ctx context.Context, from common.Address, method string, params ...interface{}) error {
+	input, err := s.abi.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+
+	msg := geth.CallMsg{
+		From: from,
+		To:   &s.address,
+		Data: input,
+	}
+
+	_, err = s.conn.GetClient().PendingCallContract(ctx, msg)
+	if err != nil {
+		reason, ok := DecodeRevertReason(err)
+		if !ok {
+			return err
+		}
+		return &RevertError{
+			Method: method,
+			Reason: reason,
+			Class:  ClassifyRevert(reason),
+		}
+	}
+
+	return nil
+}
+
+// EstimateGas estimates the gas used by the given contract method. A *RevertError is returned
+// if the call reverts.
+func (s *Simulator) EstimateGas(ctx context.Context, from common.Address, method string, params ...interface{}) (uint64, error) {
+	input, err := s.abi.Pack(method, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := geth.CallMsg{
+		From: from,
+		To:   &s.address,
+		Data: input,
+	}
+
+	gas, err := s.conn.GetClient().EstimateGas(ctx, msg)
+	if err != nil {
+		reason, ok := DecodeRevertReason(err)
+		if !ok {
+			return 0, err
+		}
+		return 0, &RevertError{
+			Method: method,
+			Reason: reason,
+			Class:  ClassifyRevert(reason),
+		}
+	}
+
+	return gas, nil
+}
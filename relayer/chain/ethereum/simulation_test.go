@@ -0,0 +1,57 @@
+package ethereum_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDataError struct {
+	message string
+	data    interface{}
+}
+
+func (e *testDataError) Error() string          { return e.message }
+func (e *testDataError) ErrorData() interface{} { return e.data }
+
+// ABI-encoded Error(string) with reason "invalid nonce"
+const invalidNonceRevertData = "0x08c379a0" +
+	"0000000000000000000000000000000000000000000000000000000000000020" +
+	"000000000000000000000000000000000000000000000000000000000000000d" +
+	"696e76616c6964206e6f6e636500000000000000000000000000000000000000"
+
+func TestDecodeRevertReason(t *testing.T) {
+	reason, ok := ethereum.DecodeRevertReason(&testDataError{
+		message: "execution reverted",
+		data:    invalidNonceRevertData,
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "invalid nonce", reason)
+
+	reason, ok = ethereum.DecodeRevertReason(errors.New("execution reverted: Invalid proof"))
+	assert.True(t, ok)
+	assert.Equal(t, "Invalid proof", reason)
+
+	reason, ok = ethereum.DecodeRevertReason(errors.New("VM Exception while processing transaction: revert Payload blocknumber is too old"))
+	assert.True(t, ok)
+	assert.Equal(t, "Payload blocknumber is too old", reason)
+
+	_, ok = ethereum.DecodeRevertReason(errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"))
+	assert.False(t, ok)
+}
+
+func TestClassifyRevert(t *testing.T) {
+	assert.Equal(t, ethereum.RevertStaleRoot, ethereum.ClassifyRevert("Invalid proof"))
+	assert.Equal(t, ethereum.RevertNonceUsed, ethereum.ClassifyRevert("invalid nonce"))
+	assert.Equal(t, ethereum.RevertNonceUsed, ethereum.ClassifyRevert("Payload blocknumber is too old"))
+	assert.Equal(t, ethereum.RevertInvalidProof, ethereum.ClassifyRevert("Error: Invalid Signature"))
+	assert.Equal(t, ethereum.RevertUnknown, ethereum.ClassifyRevert("Error: Block wait period not over"))
+}
+
+func TestClassifyNonceRevert(t *testing.T) {
+	assert.Equal(t, ethereum.RevertNonceUsed, ethereum.ClassifyNonceRevert(3, 5))
+	assert.Equal(t, ethereum.RevertNonceUsed, ethereum.ClassifyNonceRevert(5, 5))
+	assert.Equal(t, ethereum.RevertNonceGap, ethereum.ClassifyNonceRevert(7, 5))
+}
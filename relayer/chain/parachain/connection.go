@@ -13,15 +13,22 @@ import (
 	"github.com/snowfork/go-substrate-rpc-client/v3/signature"
 	"github.com/snowfork/go-substrate-rpc-client/v3/types"
 
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/metrics"
+
 	log "github.com/sirupsen/logrus"
 )
 
 type Connection struct {
-	endpoint    string
-	kp          *signature.KeyringPair
-	api         *gsrpc.SubstrateAPI
-	metadata    types.Metadata
-	genesisHash types.Hash
+	endpoint          string
+	fallbackEndpoints []string
+	kp                *signature.KeyringPair
+	api               *gsrpc.SubstrateAPI
+	metadata          types.Metadata
+	genesisHash       types.Hash
+	// Nodes from which commitments are read, in order of preference
+	commitmentSources []*commitmentSource
+	connected         *health.Condition
 }
 
 func (co *Connection) API() *gsrpc.SubstrateAPI {
@@ -36,16 +43,20 @@ func (co *Connection) Keypair() *signature.KeyringPair {
 	return co.kp
 }
 
-func NewConnection(endpoint string, kp *signature.KeyringPair) *Connection {
+// NewConnection creates a connection to the parachain node at the given endpoint. Commitments missing
+// from the offchain storage of that node are read from the nodes at the fallback endpoints.
+func NewConnection(endpoint string, kp *signature.KeyringPair, fallbackEndpoints ...string) *Connection {
 	return &Connection{
-		endpoint: endpoint,
-		kp:       kp,
+		endpoint:          endpoint,
+		fallbackEndpoints: fallbackEndpoints,
+		kp:                kp,
+		connected:         health.Expect("parachain connection to " + endpoint),
 	}
 }
 
 func (co *Connection) Connect(_ context.Context) error {
 	// Initialize API
-	api, err := gsrpc.NewSubstrateAPI(co.endpoint)
+	api, err := metrics.NewSubstrateAPI(co.endpoint)
 	if err != nil {
 		return err
 	}
@@ -70,11 +81,37 @@ func (co *Connection) Connect(_ context.Context) error {
 		"metaVersion": meta.Version,
 	}).Info("Connected to chain")
 
+	co.commitmentSources = []*commitmentSource{{endpoint: co.endpoint, api: api}}
+	for _, endpoint := range co.fallbackEndpoints {
+		fallbackAPI, err := metrics.NewSubstrateAPI(endpoint)
+		if err != nil {
+			log.WithError(err).WithField("endpoint", endpoint).Warn("Failed to connect to fallback parachain node")
+			continue
+		}
+		co.commitmentSources = append(co.commitmentSources, &commitmentSource{endpoint: endpoint, api: fallbackAPI})
+	}
+
+	co.connected.Satisfy()
+
 	return nil
 }
 
+// Close closes the connection, which then no longer counts towards readiness
 func (co *Connection) Close() {
-	// TODO: Fix design issue in GSRPC preventing on-demand closing of connections
+	if co.api != nil {
+		metrics.CloseSubstrateAPI(co.api)
+	}
+	// Closing the main node's API again is harmless
+	for _, source := range co.commitmentSources {
+		metrics.CloseSubstrateAPI(source.api)
+	}
+	co.commitmentSources = nil
+	co.connected.Remove()
+}
+
+// Disconnected marks the connection as no longer connected after a subscription through it failed
+func (co *Connection) Disconnected() {
+	co.connected.Unsatisfy()
 }
 
 func (co *Connection) GenesisHash() types.Hash {
@@ -104,28 +141,50 @@ func (co *Connection) GetLatestBlockNumber() (*types.BlockNumber, error) {
 	return &latestBlock.Block.Header.Number, nil
 }
 
+// GetDataForDigestItem reads the commitment referenced by the digest item from offchain storage,
+// trying the fallback nodes in order if the commitment is missing. A *CommitmentNotFoundError is
+// returned if no node holds the commitment.
 func (co *Connection) GetDataForDigestItem(digestItem *AuxiliaryDigestItem) (types.StorageDataRaw, error) {
 	storageKey, err := MakeStorageKey(digestItem.AsCommitment.ChannelID, digestItem.AsCommitment.Hash)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := co.API().RPC.Offchain.LocalStorageGet(offchain.Persistent, storageKey)
-	if err != nil {
-		log.WithError(err).Error("Failed to read commitment from offchain storage")
-		return nil, err
-	}
+	var lastErr error
+	var searched []string
+	for _, source := range co.commitmentSources {
+		data, err := source.api.RPC.Offchain.LocalStorageGet(offchain.Persistent, storageKey)
+		if err != nil {
+			log.WithError(err).WithField("endpoint", source.endpoint).Error("Failed to read commitment from offchain storage")
+			lastErr = err
+			continue
+		}
+
+		if data == nil {
+			log.WithFields(logrus.Fields{
+				"endpoint":       source.endpoint,
+				"commitmentHash": digestItem.AsCommitment.Hash.Hex(),
+			}).Warn("Commitment not found in offchain storage")
+			searched = append(searched, source.endpoint)
+			continue
+		}
 
-	if data != nil {
 		log.WithFields(logrus.Fields{
+			"endpoint":            source.endpoint,
 			"commitmentSizeBytes": len(*data),
 		}).Debug("Retrieved commitment from offchain storage")
-	} else {
-		log.WithError(err).Error("Commitment not found in offchain storage")
-		return nil, err
+
+		return *data, nil
+	}
+
+	if len(searched) == 0 && lastErr != nil {
+		return nil, lastErr
 	}
 
-	return *data, nil
+	return nil, &CommitmentNotFoundError{
+		Commitment: digestItem.AsCommitment,
+		Endpoints:  searched,
+	}
 }
 
 func (co *Connection) GetBasicOutboundMessages(digestItem AuxiliaryDigestItem) (
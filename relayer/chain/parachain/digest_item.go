@@ -38,10 +38,9 @@ func (a *AuxiliaryDigestItem) Decode(decoder scale.Decoder) error {
 	return nil
 }
 
-type ChannelID struct {
-	IsBasic        bool
-	IsIncentivized bool
-}
+// ChannelID is the index of a variant of the runtime's ChannelId enum. Any index is decoded, so that
+// supporting a new channel only needs the relay to register an implementation for its index.
+type ChannelID uint8
 
 func (c *ChannelID) Decode(decoder scale.Decoder) error {
 	tag, err := decoder.ReadOneByte()
@@ -49,34 +48,13 @@ func (c *ChannelID) Decode(decoder scale.Decoder) error {
 		return err
 	}
 
-	switch tag {
-	case 0:
-		c.IsBasic = true
-	case 1:
-		c.IsIncentivized = true
-	default:
-		return fmt.Errorf("No such variant for ChannelID")
-	}
+	*c = ChannelID(tag)
 
 	return nil
 }
 
 func (c ChannelID) Encode(encoder scale.Encoder) error {
-	var err error
-	switch {
-	case c.IsBasic:
-		err = encoder.PushByte(0)
-	case c.IsIncentivized:
-		err = encoder.PushByte(1)
-	default:
-		return fmt.Errorf("No such variant for ChannelID")
-	}
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return encoder.PushByte(byte(c))
 }
 
 func ExtractAuxiliaryDigestItems(digest types.Digest) ([]AuxiliaryDigestItem, error) {
@@ -0,0 +1,137 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package parachain
+
+import (
+	"fmt"
+	"strings"
+
+	gsrpc "github.com/snowfork/go-substrate-rpc-client/v3"
+	"github.com/snowfork/go-substrate-rpc-client/v3/rpc/offchain"
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Commitments are only written to offchain storage by nodes with offchain indexing enabled
+type commitmentSource struct {
+	endpoint string
+	api      *gsrpc.SubstrateAPI
+}
+
+// CommitmentNotFoundError is returned when a commitment referenced by a parachain header is missing from
+// the offchain storage of every node, usually because the nodes were not started with offchain indexing
+type CommitmentNotFoundError struct {
+	Commitment Commitment
+	Endpoints  []string
+}
+
+func (e *CommitmentNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"commitment %s not found in offchain storage of parachain nodes [%s]. "+
+			"Nodes must be started with --enable-offchain-indexing=true",
+		e.Commitment.Hash.Hex(), strings.Join(e.Endpoints, ", "),
+	)
+}
+
+// ProbeOffchainIndexing reads the latest commitment found in the last searchDepth finalized blocks from each
+// node, or the known commitment if there is none. Nodes which don't hold the commitment are no longer used
+// to read commitments, and a *CommitmentNotFoundError is returned if none of the nodes hold it.
+func (co *Connection) ProbeOffchainIndexing(searchDepth uint64, known *Commitment) error {
+	commitment, blockNumber, err := co.findLatestCommitment(searchDepth)
+	if err != nil {
+		return err
+	}
+
+	if commitment == nil {
+		if known == nil {
+			log.WithField("searchDepth", searchDepth).Warn(
+				"No recent commitment found and no probe commitment configured. " +
+					"Parachain nodes without offchain indexing will not be detected until a commitment is read")
+			return nil
+		}
+		log.WithField("searchDepth", searchDepth).Info("No recent commitment found, probing offchain indexing with configured commitment")
+		commitment = known
+	}
+
+	storageKey, err := MakeStorageKey(commitment.ChannelID, commitment.Hash)
+	if err != nil {
+		return err
+	}
+
+	var sources []*commitmentSource
+	var rejected []string
+	for _, source := range co.commitmentSources {
+		logger := log.WithFields(log.Fields{
+			"endpoint":       source.endpoint,
+			"commitmentHash": commitment.Hash.Hex(),
+			"blockNumber":    blockNumber,
+		})
+
+		data, err := source.api.RPC.Offchain.LocalStorageGet(offchain.Persistent, storageKey)
+		if err != nil {
+			logger.WithError(err).Error("Failed to probe offchain storage of parachain node")
+			return err
+		}
+
+		if data == nil {
+			logger.Error("Parachain node does not hold recent commitment. Is offchain indexing enabled?")
+			rejected = append(rejected, source.endpoint)
+			continue
+		}
+
+		logger.Debug("Parachain node holds recent commitment")
+		sources = append(sources, source)
+	}
+
+	if len(sources) == 0 {
+		return &CommitmentNotFoundError{
+			Commitment: *commitment,
+			Endpoints:  rejected,
+		}
+	}
+
+	co.commitmentSources = sources
+	return nil
+}
+
+// Returns the last commitment in the digests of the given number of finalized blocks, or nil if there is none
+func (co *Connection) findLatestCommitment(searchDepth uint64) (*Commitment, uint64, error) {
+	finalizedHeader, err := co.GetFinalizedHeader()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	finalizedNumber := uint64(finalizedHeader.Number)
+	for depth := uint64(0); depth < searchDepth && depth <= finalizedNumber; depth++ {
+		blockNumber := finalizedNumber - depth
+
+		hash, err := co.api.RPC.Chain.GetBlockHash(blockNumber)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		header, err := co.api.RPC.Chain.GetHeader(hash)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for i := len(header.Digest) - 1; i >= 0; i-- {
+			item := header.Digest[i]
+			if !item.IsOther {
+				continue
+			}
+
+			var auxDigestItem AuxiliaryDigestItem
+			err := types.DecodeFromBytes(item.AsOther, &auxDigestItem)
+			if err != nil || !auxDigestItem.IsCommitment {
+				continue
+			}
+
+			return &auxDigestItem.AsCommitment, blockNumber, nil
+		}
+	}
+
+	return nil, 0, nil
+}
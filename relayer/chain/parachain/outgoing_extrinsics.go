@@ -57,6 +57,7 @@ func (ep *ExtrinsicPool) WaitForSubmitAndWatch(
 				return nil
 			case err := <-sub.Err():
 				log.WithError(err).WithField("nonce", nonce(ext)).Error("Subscription failed for extrinsic status")
+				ep.conn.Disconnected()
 				return err
 			case status := <-sub.Chan():
 				// https://github.com/paritytech/substrate/blob/29aca981db5e8bf8b5538e6c7920ded917013ef3/primitives/transaction-pool/src/pool.rs#L56-L127
@@ -0,0 +1,94 @@
+package parachain
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+)
+
+// SplitParaHead splits the SCALE-encoded head data of a parachain around the hash of the commitment
+// for the given channel. The prefix starts with the compact-encoded length of the header, so that
+// prefix, commitment hash and suffix together form the head data stored in Paras.Heads.
+func SplitParaHead(header types.Header, channelID ChannelID, commitmentHash types.H256) ([]byte, []byte, error) {
+	encodedHeader, err := types.EncodeToBytes(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Find the offset of the digest items within the encoded header
+	offset := 0
+	fields := []interface{}{
+		header.ParentHash,
+		header.Number,
+		header.StateRoot,
+		header.ExtrinsicsRoot,
+		types.NewUCompactFromUInt(uint64(len(header.Digest))),
+	}
+	for _, field := range fields {
+		encodedField, err := types.EncodeToBytes(field)
+		if err != nil {
+			return nil, nil, err
+		}
+		offset += len(encodedField)
+	}
+
+	hashOffset := -1
+	for _, item := range header.Digest {
+		encodedItem, err := types.EncodeToBytes(item)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if hashOffset < 0 && isCommitmentDigestItem(item, channelID, commitmentHash) {
+			encodedLength, err := types.EncodeToBytes(types.NewUCompactFromUInt(uint64(len(item.AsOther))))
+			if err != nil {
+				return nil, nil, err
+			}
+			// The hash is preceded by the digest item variant, the length of the auxiliary
+			// digest item, the auxiliary digest item variant and the channel ID
+			hashOffset = offset + 1 + len(encodedLength) + 1 + 1
+		}
+
+		offset += len(encodedItem)
+	}
+
+	if offset != len(encodedHeader) {
+		return nil, nil, fmt.Errorf("unexpected encoding of header %d", header.Number)
+	}
+
+	if hashOffset < 0 {
+		return nil, nil, fmt.Errorf("commitment %s not found in digest of header %d", commitmentHash.Hex(), header.Number)
+	}
+
+	if !bytes.Equal(encodedHeader[hashOffset:hashOffset+32], commitmentHash[:]) {
+		return nil, nil, fmt.Errorf("commitment %s not found at expected offset in header %d", commitmentHash.Hex(), header.Number)
+	}
+
+	encodedHeaderLength, err := types.EncodeToBytes(types.NewUCompactFromUInt(uint64(len(encodedHeader))))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prefix := append(encodedHeaderLength, encodedHeader[:hashOffset]...)
+	suffix := encodedHeader[hashOffset+32:]
+
+	return prefix, suffix, nil
+}
+
+func isCommitmentDigestItem(item types.DigestItem, channelID ChannelID, commitmentHash types.H256) bool {
+	if !item.IsOther {
+		return false
+	}
+
+	var auxDigestItem AuxiliaryDigestItem
+	err := types.DecodeFromBytes(item.AsOther, &auxDigestItem)
+	if err != nil {
+		// Digest item was not produced by snowbridge
+		return false
+	}
+
+	return auxDigestItem.IsCommitment &&
+		auxDigestItem.AsCommitment.ChannelID == channelID &&
+		auxDigestItem.AsCommitment.Hash == commitmentHash
+}
@@ -0,0 +1,110 @@
+package parachain
+
+import (
+	"testing"
+
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func commitmentDigestItem(channelID byte, hash types.H256) types.DigestItem {
+	return types.DigestItem{
+		IsOther: true,
+		AsOther: append([]byte{0, channelID}, hash[:]...),
+	}
+}
+
+func makeTestHeader(digest types.Digest) types.Header {
+	return types.Header{
+		ParentHash:     types.NewHash([]byte{1, 2, 3}),
+		Number:         types.BlockNumber(1337),
+		StateRoot:      types.NewHash([]byte{4, 5, 6}),
+		ExtrinsicsRoot: types.NewHash([]byte{7, 8, 9}),
+		Digest:         digest,
+	}
+}
+
+func assertSplit(t *testing.T, header types.Header, channelID ChannelID, hash types.H256) []byte {
+	prefix, suffix, err := SplitParaHead(header, channelID, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encodedHeader, err := types.EncodeToBytes(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headData, err := types.EncodeToBytes(types.NewBytes(encodedHeader))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	joined := append(append(append([]byte{}, prefix...), hash[:]...), suffix...)
+	assert.Equal(t, headData, joined)
+
+	return prefix
+}
+
+func TestSplitParaHeadMultipleDigestItems(t *testing.T) {
+	hash := types.NewH256([]byte{
+		7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
+		7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
+	})
+
+	header := makeTestHeader(types.Digest{
+		{
+			IsPreRuntime: true,
+			AsPreRuntime: types.PreRuntime{ConsensusEngineID: 1635087713, Bytes: []byte{1, 2, 3, 4}},
+		},
+		commitmentDigestItem(0, hash),
+		{
+			IsSeal: true,
+			AsSeal: types.Seal{ConsensusEngineID: 1635087713, Bytes: make([]byte, 64)},
+		},
+	})
+
+	assertSplit(t, header, ChannelID(0), hash)
+
+	_, _, err := SplitParaHead(header, ChannelID(1), hash)
+	assert.Error(t, err)
+}
+
+func TestSplitParaHeadMultipleCommitments(t *testing.T) {
+	basicHash := types.NewH256([]byte{
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	})
+	incentivizedHash := types.NewH256([]byte{
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+	})
+
+	header := makeTestHeader(types.Digest{
+		commitmentDigestItem(0, basicHash),
+		commitmentDigestItem(1, incentivizedHash),
+	})
+
+	basicPrefix := assertSplit(t, header, ChannelID(0), basicHash)
+	incentivizedPrefix := assertSplit(t, header, ChannelID(1), incentivizedHash)
+
+	// The incentivized commitment follows the basic commitment, which is 36 bytes long when encoded
+	assert.Equal(t, len(basicPrefix)+36, len(incentivizedPrefix))
+}
+
+func TestSplitParaHeadRepeatedHash(t *testing.T) {
+	// The commitment hash appears in the parent hash and in the digest of both channels
+	hash := types.NewH256([]byte{
+		3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+		3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	})
+
+	header := makeTestHeader(types.Digest{
+		commitmentDigestItem(0, hash),
+		commitmentDigestItem(1, hash),
+	})
+	header.ParentHash = types.Hash(hash)
+
+	basicPrefix := assertSplit(t, header, ChannelID(0), hash)
+	incentivizedPrefix := assertSplit(t, header, ChannelID(1), hash)
+	assert.Equal(t, len(basicPrefix)+36, len(incentivizedPrefix))
+}
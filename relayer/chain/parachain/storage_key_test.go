@@ -14,9 +14,8 @@ func TestMakeStorageKey(t *testing.T) {
 		7, 7, 7, 7, 7, 7, 7, 7,
 	}
 
-	channelID := ChannelID{
-		IsBasic: true,
-	}
+	// Basic channel
+	channelID := ChannelID(0)
 
 	key, err := MakeStorageKey(channelID, commitmentHash)
 	if err != nil {
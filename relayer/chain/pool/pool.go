@@ -0,0 +1,89 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package pool shares chain connections between relays running in the same process.
+//
+// Only connections which never sign are pooled, so that relays sharing a node also share a single
+// connection to it without sharing an account. A pooled connection outlives the relays using it, which
+// lets a relay be restarted without disturbing the others. If the connection is lost, it is redialed by
+// the next relay to start.
+package pool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
+)
+
+// Connection is implemented by the connections in the pool
+type Connection interface {
+	Connect(ctx context.Context) error
+	IsConnected() bool
+}
+
+type Pool struct {
+	mu         sync.Mutex
+	ethereum   map[string]*ethereum.Connection
+	relaychain map[string]*relaychain.Connection
+}
+
+func New() *Pool {
+	return &Pool{
+		ethereum:   make(map[string]*ethereum.Connection),
+		relaychain: make(map[string]*relaychain.Connection),
+	}
+}
+
+// Ethereum returns the read-only connection to the Ethereum node at the given endpoint. It can't
+// send transactions.
+func (p *Pool) Ethereum(endpoint string) *ethereum.Connection {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn, ok := p.ethereum[endpoint]
+	if !ok {
+		conn = ethereum.NewConnection(endpoint, nil)
+		p.ethereum[endpoint] = conn
+	}
+	return conn
+}
+
+// Relaychain returns the connection to the relay chain node at the given endpoint
+func (p *Pool) Relaychain(endpoint string) *relaychain.Connection {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn, ok := p.relaychain[endpoint]
+	if !ok {
+		conn = relaychain.NewConnection(endpoint)
+		p.relaychain[endpoint] = conn
+	}
+	return conn
+}
+
+// Connect connects a connection from the pool unless it is already connected, redialing it if it
+// was lost. Relays call it instead of connecting pooled connections themselves, as another relay may
+// be using them.
+func (p *Pool) Connect(ctx context.Context, conn Connection) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn.IsConnected() {
+		return nil
+	}
+	return conn.Connect(ctx)
+}
+
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, conn := range p.ethereum {
+		conn.Close()
+	}
+	for _, conn := range p.relaychain {
+		conn.Close()
+	}
+}
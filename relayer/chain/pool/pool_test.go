@@ -0,0 +1,61 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConnection struct {
+	connects  int
+	connected bool
+	err       error
+}
+
+func (c *testConnection) Connect(_ context.Context) error {
+	c.connects++
+	c.connected = c.err == nil
+	return c.err
+}
+
+func (c *testConnection) IsConnected() bool {
+	return c.connected
+}
+
+func TestConnectionsAreSharedByEndpoint(t *testing.T) {
+	p := New()
+
+	assert.Same(t, p.Ethereum("ws://localhost:8546"), p.Ethereum("ws://localhost:8546"))
+	assert.NotSame(t, p.Ethereum("ws://localhost:8546"), p.Ethereum("ws://localhost:8547"))
+	assert.Same(t, p.Relaychain("ws://localhost:9944"), p.Relaychain("ws://localhost:9944"))
+}
+
+func TestConnectOnlyOnce(t *testing.T) {
+	p := New()
+	ctx := context.Background()
+
+	conn := &testConnection{err: fmt.Errorf("connection refused")}
+	assert.Error(t, p.Connect(ctx, conn))
+
+	// A failed connection is retried
+	conn.err = nil
+	assert.NoError(t, p.Connect(ctx, conn))
+	assert.NoError(t, p.Connect(ctx, conn))
+	assert.Equal(t, 2, conn.connects)
+}
+
+func TestConnectRedialsLostConnection(t *testing.T) {
+	p := New()
+	ctx := context.Background()
+
+	conn := &testConnection{}
+	assert.NoError(t, p.Connect(ctx, conn))
+
+	// The node dropped the connection
+	conn.connected = false
+	assert.NoError(t, p.Connect(ctx, conn))
+	assert.NoError(t, p.Connect(ctx, conn))
+	assert.Equal(t, 2, conn.connects)
+}
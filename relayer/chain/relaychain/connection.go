@@ -10,6 +10,9 @@ import (
 	gsrpc "github.com/snowfork/go-substrate-rpc-client/v3"
 	"github.com/snowfork/go-substrate-rpc-client/v3/types"
 
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/metrics"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -18,11 +21,13 @@ type Connection struct {
 	api         *gsrpc.SubstrateAPI
 	metadata    types.Metadata
 	genesisHash types.Hash
+	connected   *health.Condition
 }
 
 func NewConnection(endpoint string) *Connection {
 	return &Connection{
-		endpoint: endpoint,
+		endpoint:  endpoint,
+		connected: health.Expect("relay chain connection to " + endpoint),
 	}
 }
 
@@ -36,7 +41,7 @@ func (co *Connection) Metadata() *types.Metadata {
 
 func (co *Connection) Connect(_ context.Context) error {
 	// Initialize API
-	api, err := gsrpc.NewSubstrateAPI(co.endpoint)
+	api, err := metrics.NewSubstrateAPI(co.endpoint)
 	if err != nil {
 		return err
 	}
@@ -61,11 +66,27 @@ func (co *Connection) Connect(_ context.Context) error {
 		"metaVersion": meta.Version,
 	}).Info("Connected to chain")
 
+	co.connected.Satisfy()
+
 	return nil
 }
 
+// Close closes the connection, which then no longer counts towards readiness
 func (co *Connection) Close() {
-	// TODO: Fix design issue in GSRPC preventing on-demand closing of connections
+	if co.api != nil {
+		metrics.CloseSubstrateAPI(co.api)
+	}
+	co.connected.Remove()
+}
+
+// IsConnected returns whether the connection was established and hasn't been lost since
+func (co *Connection) IsConnected() bool {
+	return co.connected.Satisfied()
+}
+
+// Disconnected marks the connection as no longer connected after a subscription through it failed
+func (co *Connection) Disconnected() {
+	co.connected.Unsatisfy()
 }
 
 func (co *Connection) GetMMRLeafForBlock(
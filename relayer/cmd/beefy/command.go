@@ -0,0 +1,17 @@
+package beefy
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "beefy",
+		Short: "Tools for the beefy relay",
+		Args:  cobra.MinimumNArgs(1),
+	}
+
+	cmd.AddCommand(dbCommand())
+
+	return cmd
+}
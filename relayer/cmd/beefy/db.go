@@ -0,0 +1,247 @@
+package beefy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	relayconfig "github.com/snowfork/snowbridge/relayer/config"
+	"github.com/snowfork/snowbridge/relayer/relays/beefy"
+	"github.com/snowfork/snowbridge/relayer/relays/beefy/store"
+)
+
+var configFile string
+
+func dbCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Inspect and repair the beefy relay database",
+		Long: `Inspect and repair the beefy relay database.
+
+The relay must be configured with a persistent database (database.path) for these
+commands to be useful. The retry and purge commands should only be used while the
+relay is stopped.`,
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	cmd.PersistentFlags().StringVar(&configFile, "config", "", "Path to beefy relay configuration file")
+	cmd.MarkPersistentFlagRequired("config")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List all items in the database",
+		Args:  cobra.ExactArgs(0),
+		RunE:  listItems,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "show [id]",
+		Short:   "Show a single item with its decoded signed commitment",
+		Args:    cobra.ExactArgs(1),
+		Example: "snowbridge-relay beefy db show 3 --config beefy-relay.json",
+		RunE:    showItem,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "retry [id]",
+		Short:   "Reset a stuck item to status CommitmentWitnessed",
+		Args:    cobra.ExactArgs(1),
+		Example: "snowbridge-relay beefy db retry 3 --config beefy-relay.json",
+		RunE:    retryItem,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "purge [id]",
+		Short:   "Delete an item from the database",
+		Args:    cobra.ExactArgs(1),
+		Example: "snowbridge-relay beefy db purge 3 --config beefy-relay.json",
+		RunE:    purgeItem,
+	})
+
+	return cmd
+}
+
+func openDatabase() (*store.Database, error) {
+	var config beefy.Config
+	err := relayconfig.ReadFile(configFile, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Database.Path == "" {
+		return nil, fmt.Errorf("relay is not configured with a persistent database (database.path)")
+	}
+
+	_, err = os.Stat(config.Database.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database: %w", err)
+	}
+
+	db := store.NewPersistentDatabase(config.Database.Path, nil)
+	err = db.Initialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func findItem(db *store.Database, arg string) (*store.BeefyRelayInfo, error) {
+	id, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid item id %q: %w", arg, err)
+	}
+
+	item, err := db.GetItemByRowID(uint(id))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find item %d: %w", id, err)
+	}
+
+	return item, nil
+}
+
+func listItems(_ *cobra.Command, _ []string) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	items, err := db.GetItems()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tBEEFY BLOCK\tCONTRACT ID\tINITIAL TX\tCOMPLETE ON BLOCK\tCOMPLETE TX")
+	for _, item := range items {
+		blockNumber := "?"
+		justification, err := item.ToBeefyJustification()
+		if err == nil {
+			blockNumber = fmt.Sprint(justification.SignedCommitment.Commitment.BlockNumber)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%d\t%s\n",
+			item.ID,
+			item.Status,
+			blockNumber,
+			item.ContractID,
+			formatHash(item.InitialVerificationTxHash),
+			item.CompleteOnBlock,
+			formatHash(item.CompleteVerificationTxHash),
+		)
+	}
+
+	return w.Flush()
+}
+
+type itemView struct {
+	ID                         uint                   `json:"id"`
+	CreatedAt                  string                 `json:"createdAt"`
+	UpdatedAt                  string                 `json:"updatedAt"`
+	Status                     string                 `json:"status"`
+	ContractID                 int64                  `json:"contractId"`
+	MMRLeafCount               uint64                 `json:"mmrLeafCount"`
+	InitialVerificationTxHash  string                 `json:"initialVerificationTxHash"`
+	CompleteOnBlock            uint64                 `json:"completeOnBlock"`
+	RandomSeed                 string                 `json:"randomSeed"`
+	CompleteVerificationTxHash string                 `json:"completeVerificationTxHash"`
+	ValidatorAddresses         []common.Address       `json:"validatorAddresses"`
+	SignedCommitment           store.SignedCommitment `json:"signedCommitment"`
+}
+
+func showItem(_ *cobra.Command, args []string) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	item, err := findItem(db, args[0])
+	if err != nil {
+		return err
+	}
+
+	justification, err := item.ToBeefyJustification()
+	if err != nil {
+		return fmt.Errorf("unable to decode signed commitment: %w", err)
+	}
+
+	view := itemView{
+		ID:                         item.ID,
+		CreatedAt:                  item.CreatedAt.String(),
+		UpdatedAt:                  item.UpdatedAt.String(),
+		Status:                     item.Status.String(),
+		ContractID:                 item.ContractID,
+		MMRLeafCount:               item.MMRLeafCount,
+		InitialVerificationTxHash:  formatHash(item.InitialVerificationTxHash),
+		CompleteOnBlock:            item.CompleteOnBlock,
+		RandomSeed:                 formatHash(item.RandomSeed),
+		CompleteVerificationTxHash: formatHash(item.CompleteVerificationTxHash),
+		ValidatorAddresses:         justification.ValidatorAddresses,
+		SignedCommitment:           justification.SignedCommitment,
+	}
+
+	b, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+
+	return nil
+}
+
+func retryItem(_ *cobra.Command, args []string) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	item, err := findItem(db, args[0])
+	if err != nil {
+		return err
+	}
+
+	previous := item.Status
+	err = db.ResetItem(item)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Item %d reset from %s to %s\n", item.ID, previous, store.CommitmentWitnessed)
+	return nil
+}
+
+func purgeItem(_ *cobra.Command, args []string) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	item, err := findItem(db, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = db.DeleteItem(item)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Item %d deleted\n", item.ID)
+	return nil
+}
+
+func formatHash(hash common.Hash) string {
+	if hash == (common.Hash{}) {
+		return "-"
+	}
+	return hash.Hex()
+}
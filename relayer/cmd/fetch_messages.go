@@ -251,5 +251,11 @@ func printEthContractEventForSub(mapping map[common.Address]string, event *gethT
 		formatProofVec(msgInner.Proof.Data.Values),
 	)
 	fmt.Println("")
+
+	transfer, err := ethereum.DecodeTransferFromEvent(message.Call, event)
+	if err == nil {
+		fmt.Printf("// %s\n", transfer.String())
+	}
+
 	return nil
 }
@@ -0,0 +1,291 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/google/uuid"
+	"github.com/snowfork/go-substrate-rpc-client/v3/hash"
+	"github.com/spf13/cobra"
+	"github.com/vedhavyas/go-subkey"
+
+	"github.com/snowfork/snowbridge/relayer/crypto"
+	"github.com/snowfork/snowbridge/relayer/crypto/ed25519"
+	relayerkeystore "github.com/snowfork/snowbridge/relayer/crypto/keystore"
+	"github.com/snowfork/snowbridge/relayer/crypto/secp256k1"
+	"github.com/snowfork/snowbridge/relayer/crypto/sr25519"
+	"github.com/snowfork/snowbridge/relayer/relays/beefy"
+)
+
+var (
+	keyType        string
+	outputFile     string
+	encrypt        bool
+	keyFile        string
+	keystoreFile   string
+	passphraseFile string
+	ss58Prefix     uint8
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Generate and inspect relayer keys",
+		Args:  cobra.MinimumNArgs(1),
+	}
+
+	cmd.PersistentFlags().Uint8Var(&ss58Prefix, "ss58-prefix", 42, "SS58 network prefix of Substrate addresses")
+
+	cmd.AddCommand(generateCommand())
+	cmd.AddCommand(inspectCommand())
+	cmd.AddCommand(beefyAuthorityCommand())
+
+	return cmd
+}
+
+func generateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a key and write it to a file",
+		Long: `Generate a key and write it to a file which can be passed to the relays.
+
+sr25519 keys are used by the ethereum relay to sign parachain extrinsics, and secp256k1
+keys are used by the beefy and parachain relays to sign Ethereum transactions.
+
+Without --encrypt, the private key URI or hex private key is written as plain text, for
+use with --substrate.private-key-file or --ethereum.private-key-file. With --encrypt,
+secp256k1 keys are written as go-ethereum JSON keystores and sr25519 keys as Polkadot-JS
+JSON exports, for use with --ethereum.keystore or --substrate.keystore. The passphrase is
+read from --passphrase-file, or else from the ` + relayerkeystore.PassphraseEnv + ` environment variable.`,
+		Args:    cobra.ExactArgs(0),
+		Example: "snowbridge-relay keys generate --type secp256k1 --output ethereum-key.json --encrypt --passphrase-file passphrase.txt",
+		RunE:    generate,
+	}
+
+	cmd.Flags().StringVar(&keyType, "type", crypto.Sr25519Type, "Type of key (sr25519 or secp256k1)")
+	cmd.Flags().StringVar(&outputFile, "output", "", "File to write the key to. It must not exist.")
+	cmd.MarkFlagRequired("output")
+	cmd.Flags().BoolVar(&encrypt, "encrypt", false, "Encrypt the key with a passphrase")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "The file from which to read the passphrase")
+
+	return cmd
+}
+
+func inspectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect [uri]",
+		Short: "Show the public key and addresses of a key",
+		Long: `Show the public key and addresses of a key, which is given as a private key URI or hex
+private key argument, a plain text key file, or an encrypted keystore file.
+
+The SS58 address of a secp256k1 key is that of its Substrate ECDSA account, whose ID is the
+blake2b-256 hash of the compressed public key.`,
+		Args:    cobra.MaximumNArgs(1),
+		Example: "snowbridge-relay keys inspect --type sr25519 //Relay",
+		RunE:    inspect,
+	}
+
+	cmd.Flags().StringVar(&keyType, "type", crypto.Sr25519Type, "Type of key (sr25519, secp256k1 or ed25519)")
+	cmd.Flags().StringVar(&keyFile, "file", "", "Plain text file holding the key")
+	cmd.Flags().StringVar(&keystoreFile, "keystore", "", "Encrypted keystore file holding the key")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "The file from which to read the keystore passphrase")
+
+	return cmd
+}
+
+func beefyAuthorityCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "beefy-authority [public key]",
+		Short: "Show the Ethereum address of a BEEFY authority",
+		Long: `Show the Ethereum address which the BEEFY light client uses for the BEEFY authority with
+the given hex encoded compressed ECDSA public key.`,
+		Args:    cobra.ExactArgs(1),
+		Example: "snowbridge-relay keys beefy-authority 0x020a1091341fe5664bfa1782d5e04779689068c916b04cb365ec3153755684d9a1",
+		RunE:    beefyAuthority,
+	}
+}
+
+func generate(_ *cobra.Command, _ []string) error {
+	var keypair crypto.Keypair
+	var content []byte
+
+	switch keyType {
+	case crypto.Sr25519Type:
+		kp, err := sr25519.GenerateKeypair(ss58Prefix)
+		if err != nil {
+			return err
+		}
+		keypair = kp
+
+		if encrypt {
+			passphrase, err := relayerkeystore.Passphrase(passphraseFile)
+			if err != nil {
+				return err
+			}
+			content, err = kp.Export(passphrase)
+			if err != nil {
+				return err
+			}
+		} else {
+			content = []byte(kp.AsKeyringPair().URI)
+		}
+	case crypto.Secp256k1Type:
+		kp, err := secp256k1.GenerateKeypair()
+		if err != nil {
+			return err
+		}
+		keypair = kp
+
+		if encrypt {
+			passphrase, err := relayerkeystore.Passphrase(passphraseFile)
+			if err != nil {
+				return err
+			}
+			key := &keystore.Key{
+				Id:         uuid.New(),
+				Address:    kp.CommonAddress(),
+				PrivateKey: kp.PrivateKey(),
+			}
+			content, err = keystore.EncryptKey(key, passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+			if err != nil {
+				return err
+			}
+		} else {
+			content = []byte(hexutil.Encode(kp.Encode()))
+		}
+	default:
+		return fmt.Errorf("unsupported key type %q", keyType)
+	}
+
+	// Only the owner may read the key, as the relays require
+	file, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(content, '\n'))
+	if err != nil {
+		file.Close()
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return printKey(keypair)
+}
+
+func inspect(_ *cobra.Command, args []string) error {
+	keypair, err := readKey(args)
+	if err != nil {
+		return err
+	}
+	return printKey(keypair)
+}
+
+func readKey(args []string) (crypto.Keypair, error) {
+	if keystoreFile != "" {
+		content, err := relayerkeystore.ReadFile(keystoreFile)
+		if err != nil {
+			return nil, err
+		}
+		passphrase, err := relayerkeystore.Passphrase(passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+
+		switch keyType {
+		case crypto.Sr25519Type:
+			return sr25519.NewKeypairFromExport(content, passphrase, ss58Prefix)
+		case crypto.Secp256k1Type:
+			return secp256k1.NewKeypairFromKeystore(content, passphrase)
+		default:
+			return nil, fmt.Errorf("keystores are not supported for key type %q", keyType)
+		}
+	}
+
+	var uri string
+	switch {
+	case keyFile != "":
+		content, err := relayerkeystore.ReadFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		uri = strings.TrimSpace(string(content))
+	case len(args) == 1:
+		uri = args[0]
+	default:
+		return nil, fmt.Errorf("a key URI, --file or --keystore is required")
+	}
+
+	switch keyType {
+	case crypto.Sr25519Type:
+		return sr25519.NewKeypairFromSeed(uri, ss58Prefix)
+	case crypto.Ed25519Type:
+		return ed25519.NewKeypairFromSeed(uri, ss58Prefix)
+	case crypto.Secp256k1Type:
+		return secp256k1.NewKeypairFromString(strings.TrimPrefix(uri, "0x"))
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+}
+
+func beefyAuthority(_ *cobra.Command, args []string) error {
+	publicKey, err := hexutil.Decode(args[0])
+	if err != nil {
+		return fmt.Errorf("decode public key: %w", err)
+	}
+
+	var authority [33]uint8
+	if len(publicKey) != len(authority) {
+		return fmt.Errorf("expected a %d byte compressed public key, got %d bytes", len(authority), len(publicKey))
+	}
+	copy(authority[:], publicKey)
+
+	address, err := beefy.AuthorityAddress(authority)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(address.Hex())
+	return nil
+}
+
+func printKey(keypair crypto.Keypair) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Key type:\t%s\n", keypair.Type())
+	fmt.Fprintf(w, "Public key:\t%s\n", keypair.PublicKey())
+
+	switch kp := keypair.(type) {
+	case *secp256k1.Keypair:
+		ss58Address, err := ecdsaSS58Address(kp)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "SS58 address:\t%s\n", ss58Address)
+		fmt.Fprintf(w, "Ethereum address:\t%s\n", kp.Address())
+	default:
+		fmt.Fprintf(w, "SS58 address:\t%s\n", keypair.Address())
+	}
+
+	return w.Flush()
+}
+
+// Substrate derives the account ID of an ECDSA key by hashing its compressed public key
+func ecdsaSS58Address(kp *secp256k1.Keypair) (string, error) {
+	publicKey, err := hexutil.Decode(kp.PublicKey())
+	if err != nil {
+		return "", err
+	}
+	hasher, err := hash.NewBlake2b256(nil)
+	if err != nil {
+		return "", err
+	}
+	hasher.Write(publicKey)
+	return subkey.SS58Address(hasher.Sum(nil), ss58Prefix)
+}
@@ -0,0 +1,17 @@
+package parachain
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "parachain",
+		Short: "Tools for the parachain relay",
+		Args:  cobra.MinimumNArgs(1),
+	}
+
+	cmd.AddCommand(deadLettersCommand())
+
+	return cmd
+}
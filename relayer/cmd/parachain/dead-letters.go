@@ -0,0 +1,236 @@
+package parachain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	relayconfig "github.com/snowfork/snowbridge/relayer/config"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain/store"
+)
+
+var configFile string
+
+func deadLettersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dead-letters",
+		Short: "Inspect and replay message packages which failed to be delivered",
+		Long: `Inspect and replay message packages which failed to be delivered.
+
+A message package whose delivery keeps failing is dead-lettered once it has used up its
+attempts (sink.max-package-attempts). No later package on its channel is delivered until
+it is replayed or purged.
+The relay must be configured with a persistent database (database.path) for these
+commands to be useful. Packages can be replayed while the relay is running, and are
+delivered again when the relay next finds them undelivered.`,
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	cmd.PersistentFlags().StringVar(&configFile, "config", "", "Path to parachain relay configuration file")
+	cmd.MarkPersistentFlagRequired("config")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List message packages which have failed, dead-lettered or not",
+		Args:  cobra.ExactArgs(0),
+		RunE:  listFailures,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "show [id]",
+		Short:   "Show a failed message package with its commitment data",
+		Args:    cobra.ExactArgs(1),
+		Example: "snowbridge-relay parachain dead-letters show 3 --config parachain-relay.json",
+		RunE:    showFailure,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "replay [id]",
+		Short:   "Reset the attempts of a message package so that the relay delivers it again",
+		Args:    cobra.ExactArgs(1),
+		Example: "snowbridge-relay parachain dead-letters replay 3 --config parachain-relay.json",
+		RunE:    replayFailure,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "purge [id]",
+		Short:   "Delete a failed message package from the database",
+		Args:    cobra.ExactArgs(1),
+		Example: "snowbridge-relay parachain dead-letters purge 3 --config parachain-relay.json",
+		RunE:    purgeFailure,
+	})
+
+	return cmd
+}
+
+func openDatabase() (*store.Database, error) {
+	var config parachain.Config
+	err := relayconfig.ReadFile(configFile, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Database.Path == "" {
+		return nil, fmt.Errorf("relay is not configured with a persistent database (database.path)")
+	}
+
+	_, err = os.Stat(config.Database.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database: %w", err)
+	}
+
+	db := store.NewDatabase(config.Database.Path)
+	err = db.Initialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func findFailure(db *store.Database, arg string) (*store.FailedMessagePackage, error) {
+	id, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message package id %q: %w", arg, err)
+	}
+
+	failure, err := db.GetFailedMessagePackage(uint(id))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find message package %d: %w", id, err)
+	}
+
+	return failure, nil
+}
+
+func failureStatus(failure *store.FailedMessagePackage) string {
+	if failure.DeadLettered {
+		return "dead-lettered"
+	}
+	return "retrying"
+}
+
+func listFailures(_ *cobra.Command, _ []string) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	failures, err := db.GetFailedMessagePackages()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tCHANNEL\tPARA BLOCK\tCOMMITMENT HASH\tATTEMPTS\tLAST FAILED\tREASON")
+	for i := range failures {
+		failure := &failures[i]
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%d\t%s\t%s\n",
+			failure.ID,
+			failureStatus(failure),
+			failure.Channel,
+			failure.ParaBlock,
+			failure.CommitmentHash.Hex(),
+			failure.Attempts,
+			failure.UpdatedAt.Format("2006-01-02 15:04:05"),
+			failure.Reason,
+		)
+	}
+
+	return w.Flush()
+}
+
+type failureView struct {
+	ID             uint   `json:"id"`
+	CreatedAt      string `json:"createdAt"`
+	UpdatedAt      string `json:"updatedAt"`
+	Status         string `json:"status"`
+	Channel        string `json:"channel"`
+	ParaBlock      uint64 `json:"paraBlock"`
+	CommitmentHash string `json:"commitmentHash"`
+	Attempts       uint64 `json:"attempts"`
+	Reason         string `json:"reason"`
+	CommitmentData string `json:"commitmentData"`
+}
+
+func showFailure(_ *cobra.Command, args []string) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	failure, err := findFailure(db, args[0])
+	if err != nil {
+		return err
+	}
+
+	view := failureView{
+		ID:             failure.ID,
+		CreatedAt:      failure.CreatedAt.String(),
+		UpdatedAt:      failure.UpdatedAt.String(),
+		Status:         failureStatus(failure),
+		Channel:        failure.Channel,
+		ParaBlock:      failure.ParaBlock,
+		CommitmentHash: failure.CommitmentHash.Hex(),
+		Attempts:       failure.Attempts,
+		Reason:         failure.Reason,
+		CommitmentData: hexutil.Encode(failure.CommitmentData),
+	}
+
+	b, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+
+	return nil
+}
+
+func replayFailure(_ *cobra.Command, args []string) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	failure, err := findFailure(db, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = db.ReplayFailedMessagePackage(failure)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Message package %d will be delivered again when the relay next finds it undelivered\n", failure.ID)
+	return nil
+}
+
+func purgeFailure(_ *cobra.Command, args []string) error {
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	failure, err := findFailure(db, args[0])
+	if err != nil {
+		return err
+	}
+
+	err = db.DeleteFailedMessagePackage(failure)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Message package %d deleted\n", failure.ID)
+	return nil
+}
@@ -6,7 +6,12 @@ package cmd
 import (
 	"os"
 
+	"github.com/snowfork/snowbridge/relayer/cmd/beefy"
+	"github.com/snowfork/snowbridge/relayer/cmd/keys"
+	"github.com/snowfork/snowbridge/relayer/cmd/parachain"
 	"github.com/snowfork/snowbridge/relayer/cmd/run"
+	"github.com/snowfork/snowbridge/relayer/cmd/status"
+	"github.com/snowfork/snowbridge/relayer/cmd/track"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +29,11 @@ func init() {
 	rootCmd.AddCommand(getBlockCmd())
 	rootCmd.AddCommand(fetchMessagesCmd())
 	rootCmd.AddCommand(subBeefyCmd())
+	rootCmd.AddCommand(beefy.Command())
+	rootCmd.AddCommand(status.Command())
+	rootCmd.AddCommand(track.Command())
+	rootCmd.AddCommand(keys.Command())
+	rootCmd.AddCommand(parachain.Command())
 }
 
 func Execute() {
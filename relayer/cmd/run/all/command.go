@@ -0,0 +1,224 @@
+package all
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/snowfork/snowbridge/relayer/chain/pool"
+	"github.com/snowfork/snowbridge/relayer/cmd/run/keys"
+	"github.com/snowfork/snowbridge/relayer/crypto/keystore"
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/metrics"
+	"github.com/snowfork/snowbridge/relayer/relays/beefy"
+	"github.com/snowfork/snowbridge/relayer/relays/ethereum"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain"
+	"github.com/snowfork/snowbridge/relayer/relays/supervisor"
+)
+
+// Config holds the configuration of each relay. Relays whose section is missing aren't run.
+type Config struct {
+	Ethereum  *ethereum.Config  `mapstructure:"ethereum"`
+	Beefy     *beefy.Config     `mapstructure:"beefy"`
+	Parachain *parachain.Config `mapstructure:"parachain"`
+}
+
+// Flags locating the Ethereum key of the beefy or parachain relay
+type ethereumKeyFlags struct {
+	privateKey          string
+	privateKeyFile      string
+	keystoreFile        string
+	passphraseFile      string
+	remoteSignerURL     string
+	remoteSignerAddress string
+}
+
+var (
+	configFile     string
+	beefyKey       ethereumKeyFlags
+	parachainKey   ethereumKeyFlags
+	privateKey     string
+	privateKeyFile string
+	keystoreFile   string
+	passphraseFile string
+	ss58Prefix     uint8
+	metricsAddr    string
+	stallTimeout   time.Duration
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "all",
+		Short: "Start every relay in a single process",
+		Long: `Start the ethereum, beefy and parachain relays in a single process.
+
+The configuration file has an "ethereum", "beefy" and "parachain" section, each holding the
+configuration of that relay. A relay is only run if its section is present. Relays connecting
+to the same Ethereum or relay chain node share a connection where they only read from it.
+
+Each relay is restarted on its own if it fails, after a delay which grows with consecutive
+failures, while the other relays keep running.`,
+		Args: cobra.ExactArgs(0),
+		RunE: run,
+	}
+
+	cmd.Flags().StringVar(&configFile, "config", "", "Path to configuration file")
+	cmd.MarkFlagRequired("config")
+
+	beefyKey.register(cmd, "beefy")
+	parachainKey.register(cmd, "parachain")
+
+	cmd.Flags().StringVar(&privateKey, "ethereum.substrate.private-key", "", "Private key URI for Substrate used by the ethereum relay")
+	cmd.Flags().StringVar(&privateKeyFile, "ethereum.substrate.private-key-file", "", "The file from which to read the private key URI")
+	cmd.Flags().StringVar(&keystoreFile, "ethereum.substrate.keystore", "", "Encrypted JSON account export from Polkadot-JS holding the private key")
+	cmd.Flags().StringVar(&passphraseFile, "ethereum.substrate.keystore-passphrase-file", "", "The file from which to read the keystore passphrase. Read from "+keystore.PassphraseEnv+" if not set")
+	cmd.Flags().Uint8Var(&ss58Prefix, "ethereum.substrate.ss58-prefix", 42, "SS58 network prefix of the relayer account address")
+
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Address on which to serve Prometheus metrics and health checks, e.g. :9090. Disabled if empty")
+	cmd.Flags().DurationVar(&stallTimeout, "health-stall-timeout", health.DefaultStallTimeout, "Time without progress after which the relays are reported unhealthy")
+
+	return cmd
+}
+
+func (f *ethereumKeyFlags) register(cmd *cobra.Command, relay string) {
+	prefix := relay + ".ethereum."
+	cmd.Flags().StringVar(&f.privateKey, prefix+"private-key", "", "Ethereum private key used by the "+relay+" relay")
+	cmd.Flags().StringVar(&f.privateKeyFile, prefix+"private-key-file", "", "The file from which to read the private key")
+	cmd.Flags().StringVar(&f.keystoreFile, prefix+"keystore", "", "Encrypted JSON keystore file holding the private key")
+	cmd.Flags().StringVar(&f.passphraseFile, prefix+"keystore-passphrase-file", "", "The file from which to read the keystore passphrase. Read from "+keystore.PassphraseEnv+" if not set")
+	cmd.Flags().StringVar(&f.remoteSignerURL, prefix+"remote-signer-url", "", "URL of a Web3Signer compatible service holding the private key, used instead of a local key")
+	cmd.Flags().StringVar(&f.remoteSignerAddress, prefix+"remote-signer-address", "", "Address of the account whose key is held by the remote signer")
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	log.SetOutput(logrus.WithFields(logrus.Fields{"logger": "stdlib"}).WriterLevel(logrus.InfoLevel))
+	logrus.SetLevel(logrus.DebugLevel)
+
+	viper.SetConfigFile(configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		return err
+	}
+
+	var config Config
+	err := viper.Unmarshal(&config)
+	if err != nil {
+		return err
+	}
+
+	connections := pool.New()
+	defer connections.Close()
+
+	relays := supervisor.New()
+	err = addRelays(relays, &config, connections)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	// Ensure clean termination upon SIGINT, SIGTERM
+	eg.Go(func() error {
+		notify := make(chan os.Signal, 1)
+		signal.Notify(notify, syscall.SIGINT, syscall.SIGTERM)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sig := <-notify:
+			logrus.WithField("signal", sig.String()).Info("Received signal")
+			cancel()
+		}
+
+		return nil
+	})
+
+	health.SetStallTimeout(stallTimeout)
+	if metricsAddr != "" {
+		err = metrics.Serve(ctx, eg, metricsAddr)
+		if err != nil {
+			cancel()
+			return err
+		}
+	}
+
+	// The supervisor only becomes ready once every relay has started
+	health.Started()
+
+	eg.Go(func() error {
+		return relays.Run(ctx)
+	})
+
+	err = eg.Wait()
+	if err != nil && err != context.Canceled {
+		logrus.WithError(err).Fatal("Unhandled error")
+		return err
+	}
+
+	return nil
+}
+
+// Keys are resolved before any relay starts, so that a missing or bad key fails startup instead of
+// failing every restart of the relay
+func addRelays(relays *supervisor.Supervisor, config *Config, connections *pool.Pool) error {
+	if config.Ethereum == nil && config.Beefy == nil && config.Parachain == nil {
+		return fmt.Errorf("no relays configured")
+	}
+
+	if config.Ethereum != nil {
+		keypair, err := keys.ResolveSubstrateKeypair(privateKey, privateKeyFile, keystoreFile, passphraseFile, ss58Prefix)
+		if err != nil {
+			return fmt.Errorf("ethereum relay: %w", err)
+		}
+		relays.Add("ethereum", func() (supervisor.Relay, error) {
+			return ethereum.NewRelay(config.Ethereum, keypair, connections), nil
+		})
+	}
+
+	if config.Beefy != nil {
+		signer, err := keys.ResolveEthereumSigner(
+			beefyKey.privateKey,
+			beefyKey.privateKeyFile,
+			beefyKey.keystoreFile,
+			beefyKey.passphraseFile,
+			beefyKey.remoteSignerURL,
+			beefyKey.remoteSignerAddress,
+		)
+		if err != nil {
+			return fmt.Errorf("beefy relay: %w", err)
+		}
+		relays.Add("beefy", func() (supervisor.Relay, error) {
+			return beefy.NewRelay(config.Beefy, signer, connections)
+		})
+	}
+
+	if config.Parachain != nil {
+		signer, err := keys.ResolveEthereumSigner(
+			parachainKey.privateKey,
+			parachainKey.privateKeyFile,
+			parachainKey.keystoreFile,
+			parachainKey.passphraseFile,
+			parachainKey.remoteSignerURL,
+			parachainKey.remoteSignerAddress,
+		)
+		if err != nil {
+			return fmt.Errorf("parachain relay: %w", err)
+		}
+		relays.Add("parachain", func() (supervisor.Relay, error) {
+			return parachain.NewRelay(config.Parachain, signer, connections)
+		})
+	}
+
+	return nil
+}
@@ -2,17 +2,20 @@ package beefy
 
 import (
 	"context"
-	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
-	"github.com/snowfork/snowbridge/relayer/crypto/secp256k1"
+	"github.com/snowfork/snowbridge/relayer/chain/pool"
+	"github.com/snowfork/snowbridge/relayer/cmd/run/keys"
+	"github.com/snowfork/snowbridge/relayer/crypto/keystore"
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/metrics"
 	"github.com/snowfork/snowbridge/relayer/relays/beefy"
+	"github.com/snowfork/snowbridge/relayer/relays/supervisor"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
@@ -22,6 +25,12 @@ var (
 	configFile string
 	privateKey string
 	privateKeyFile string
+	keystoreFile string
+	passphraseFile string
+	remoteSignerURL string
+	remoteSignerAddress string
+	metricsAddr string
+	stallTimeout time.Duration
 )
 
 func Command() *cobra.Command {
@@ -37,6 +46,13 @@ func Command() *cobra.Command {
 
 	cmd.Flags().StringVar(&privateKey, "ethereum.private-key", "", "Ethereum private key")
 	cmd.Flags().StringVar(&privateKeyFile, "ethereum.private-key-file", "", "The file from which to read the private key")
+	cmd.Flags().StringVar(&keystoreFile, "ethereum.keystore", "", "Encrypted JSON keystore file holding the private key")
+	cmd.Flags().StringVar(&passphraseFile, "ethereum.keystore-passphrase-file", "", "The file from which to read the keystore passphrase. Read from "+keystore.PassphraseEnv+" if not set")
+	cmd.Flags().StringVar(&remoteSignerURL, "ethereum.remote-signer-url", "", "URL of a Web3Signer compatible service holding the private key, used instead of a local key")
+	cmd.Flags().StringVar(&remoteSignerAddress, "ethereum.remote-signer-address", "", "Address of the account whose key is held by the remote signer")
+
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Address on which to serve Prometheus metrics and health checks, e.g. :9090. Disabled if empty")
+	cmd.Flags().DurationVar(&stallTimeout, "health-stall-timeout", health.DefaultStallTimeout, "Time without progress after which the relay is reported unhealthy")
 
 	return cmd
 }
@@ -56,15 +72,16 @@ func run(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keypair, err := resolvePrivateKey(privateKey, privateKeyFile)
+	signer, err := keys.ResolveEthereumSigner(privateKey, privateKeyFile, keystoreFile, passphraseFile, remoteSignerURL, remoteSignerAddress)
 	if err != nil {
 		return err
 	}
 
-	relay, err := beefy.NewRelay(&config, keypair)
-	if err != nil {
-		return err
-	}
+	connections := pool.New()
+	relays := supervisor.New()
+	relays.Add("beefy", func() (supervisor.Relay, error) {
+		return beefy.NewRelay(&config, signer, connections)
+	})
 
 	ctx, cancel := context.WithCancel(context.Background())
 	eg, ctx := errgroup.WithContext(ctx)
@@ -85,13 +102,21 @@ func run(_ *cobra.Command, _ []string) error {
 		return nil
 	})
 
-	err = relay.Start(ctx, eg)
-	if err != nil {
-		logrus.WithError(err).Fatal("Unhandled error")
-		cancel()
-		return err
+	health.SetStallTimeout(stallTimeout)
+	if metricsAddr != "" {
+		err = metrics.Serve(ctx, eg, metricsAddr)
+		if err != nil {
+			cancel()
+			return err
+		}
 	}
 
+	// Failed relays are restarted, so only a fatal error ends the process
+	health.Started()
+	eg.Go(func() error {
+		return relays.Run(ctx)
+	})
+
 	err = eg.Wait()
 	if err != nil {
 		logrus.WithError(err).Fatal("Unhandled error")
@@ -100,27 +125,3 @@ func run(_ *cobra.Command, _ []string) error {
 
 	return nil
 }
-
-func resolvePrivateKey(privateKey, privateKeyFile string) (*secp256k1.Keypair, error) {
-	var cleanedKey string
-
-	if privateKey == "" {
-		if privateKeyFile == "" {
-			return nil, fmt.Errorf("private key not supplied")
-		}
-		contentBytes, err := ioutil.ReadFile(privateKeyFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load private key: %w", err)
-		}
-		cleanedKey = strings.TrimPrefix(strings.TrimSpace(string(contentBytes)), "0x")
-	} else {
-		cleanedKey = strings.TrimPrefix(privateKey, "0x")
-	}
-
-	keypair, err := secp256k1.NewKeypairFromString(cleanedKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
-	}
-
-	return keypair, nil
-}
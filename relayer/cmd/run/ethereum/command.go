@@ -2,17 +2,20 @@ package ethereum
 
 import (
 	"context"
-	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
-	"github.com/snowfork/snowbridge/relayer/crypto/sr25519"
+	"github.com/snowfork/snowbridge/relayer/chain/pool"
+	"github.com/snowfork/snowbridge/relayer/cmd/run/keys"
+	"github.com/snowfork/snowbridge/relayer/crypto/keystore"
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/metrics"
 	"github.com/snowfork/snowbridge/relayer/relays/ethereum"
+	"github.com/snowfork/snowbridge/relayer/relays/supervisor"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
@@ -22,6 +25,11 @@ var (
 	configFile string
 	privateKey string
 	privateKeyFile string
+	keystoreFile string
+	passphraseFile string
+	ss58Prefix uint8
+	metricsAddr string
+	stallTimeout time.Duration
 )
 
 func Command() *cobra.Command {
@@ -37,6 +45,12 @@ func Command() *cobra.Command {
 
 	cmd.Flags().StringVar(&privateKey, "substrate.private-key", "", "Private key URI for Substrate")
 	cmd.Flags().StringVar(&privateKeyFile, "substrate.private-key-file", "", "The file from which to read the private key URI")
+	cmd.Flags().StringVar(&keystoreFile, "substrate.keystore", "", "Encrypted JSON account export from Polkadot-JS holding the private key")
+	cmd.Flags().StringVar(&passphraseFile, "substrate.keystore-passphrase-file", "", "The file from which to read the keystore passphrase. Read from "+keystore.PassphraseEnv+" if not set")
+	cmd.Flags().Uint8Var(&ss58Prefix, "substrate.ss58-prefix", 42, "SS58 network prefix of the relayer account address")
+
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Address on which to serve Prometheus metrics and health checks, e.g. :9090. Disabled if empty")
+	cmd.Flags().DurationVar(&stallTimeout, "health-stall-timeout", health.DefaultStallTimeout, "Time without progress after which the relay is reported unhealthy")
 
 	return cmd
 }
@@ -56,15 +70,16 @@ func run(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	keypair, err := resolvePrivateKey(privateKey, privateKeyFile)
+	keypair, err := keys.ResolveSubstrateKeypair(privateKey, privateKeyFile, keystoreFile, passphraseFile, ss58Prefix)
 	if err != nil {
 		return err
 	}
 
-	relay := ethereum.NewRelay(&config, keypair)
-	if err != nil {
-		return err
-	}
+	connections := pool.New()
+	relays := supervisor.New()
+	relays.Add("ethereum", func() (supervisor.Relay, error) {
+		return ethereum.NewRelay(&config, keypair, connections), nil
+	})
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -87,13 +102,21 @@ func run(_ *cobra.Command, _ []string) error {
 		return nil
 	})
 
-	err = relay.Start(ctx, eg)
-	if err != nil {
-		logrus.WithError(err).Fatal("Unhandled error")
-		cancel()
-		return err
+	health.SetStallTimeout(stallTimeout)
+	if metricsAddr != "" {
+		err = metrics.Serve(ctx, eg, metricsAddr)
+		if err != nil {
+			cancel()
+			return err
+		}
 	}
 
+	// Failed relays are restarted, so only a fatal error ends the process
+	health.Started()
+	eg.Go(func() error {
+		return relays.Run(ctx)
+	})
+
 	err = eg.Wait()
 	if err != nil {
 		logrus.WithError(err).Fatal("Unhandled error")
@@ -102,28 +125,3 @@ func run(_ *cobra.Command, _ []string) error {
 
 	return nil
 }
-
-func resolvePrivateKey(privateKey, privateKeyFile string) (*sr25519.Keypair, error) {
-	var cleanedKeyURI string
-
-	if privateKey == "" {
-		if privateKeyFile == "" {
-			return nil, fmt.Errorf("Private key URI not supplied")
-		}
-		content, err := ioutil.ReadFile(privateKeyFile)
-		if err != nil {
-			log.Fatal(err)
-		}
-		cleanedKeyURI = strings.TrimSpace(string(content))
-	} else {
-		cleanedKeyURI = privateKey
-	}
-
-	keypair, err := sr25519.NewKeypairFromSeed(cleanedKeyURI, 42)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to parse private key URI: %w", err)
-	}
-
-	return keypair, nil
-}
-
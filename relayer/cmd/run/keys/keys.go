@@ -0,0 +1,124 @@
+// Package keys resolves the keys with which the relays sign, from the command line flags of the run
+// commands.
+package keys
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/snowfork/snowbridge/relayer/crypto"
+	"github.com/snowfork/snowbridge/relayer/crypto/keystore"
+	"github.com/snowfork/snowbridge/relayer/crypto/secp256k1"
+	"github.com/snowfork/snowbridge/relayer/crypto/sr25519"
+	"github.com/snowfork/snowbridge/relayer/crypto/web3signer"
+)
+
+// ResolveEthereumSigner returns the remote signer if one is configured, and otherwise the private key
+func ResolveEthereumSigner(privateKey, privateKeyFile, keystoreFile, passphraseFile, remoteSignerURL, remoteSignerAddress string) (crypto.Signer, error) {
+	if remoteSignerURL == "" {
+		keypair, err := resolveEthereumKey(privateKey, privateKeyFile, keystoreFile, passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		return keypair, nil
+	}
+
+	if !common.IsHexAddress(remoteSignerAddress) {
+		return nil, fmt.Errorf("remote signer address not supplied")
+	}
+
+	signer, err := web3signer.NewSigner(remoteSignerURL, common.HexToAddress(remoteSignerAddress))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+	err = signer.CheckAccount(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+func resolveEthereumKey(privateKey, privateKeyFile, keystoreFile, passphraseFile string) (*secp256k1.Keypair, error) {
+	var cleanedKey string
+
+	if keystoreFile != "" {
+		keyJSON, err := keystore.ReadFile(keystoreFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load keystore: %w", err)
+		}
+		passphrase, err := keystore.Passphrase(passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		keypair, err := secp256k1.NewKeypairFromKeystore(keyJSON, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+		}
+		return keypair, nil
+	}
+
+	if privateKey == "" {
+		if privateKeyFile == "" {
+			return nil, fmt.Errorf("private key not supplied")
+		}
+		contentBytes, err := keystore.ReadFile(privateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load private key: %w", err)
+		}
+		cleanedKey = strings.TrimPrefix(strings.TrimSpace(string(contentBytes)), "0x")
+	} else {
+		cleanedKey = strings.TrimPrefix(privateKey, "0x")
+	}
+
+	keypair, err := secp256k1.NewKeypairFromString(cleanedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return keypair, nil
+}
+
+// ResolveSubstrateKeypair reads the sr25519 key from the keystore, the key file or the key URI, in that order
+func ResolveSubstrateKeypair(privateKey, privateKeyFile, keystoreFile, passphraseFile string, ss58Prefix uint8) (*sr25519.Keypair, error) {
+	var cleanedKeyURI string
+
+	if keystoreFile != "" {
+		exportJSON, err := keystore.ReadFile(keystoreFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load keystore: %w", err)
+		}
+		passphrase, err := keystore.Passphrase(passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		keypair, err := sr25519.NewKeypairFromExport(exportJSON, passphrase, ss58Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decrypt keystore: %w", err)
+		}
+		return keypair, nil
+	}
+
+	if privateKey == "" {
+		if privateKeyFile == "" {
+			return nil, fmt.Errorf("Private key URI not supplied")
+		}
+		content, err := keystore.ReadFile(privateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load private key URI: %w", err)
+		}
+		cleanedKeyURI = strings.TrimSpace(string(content))
+	} else {
+		cleanedKeyURI = privateKey
+	}
+
+	keypair, err := sr25519.NewKeypairFromSeed(cleanedKeyURI, ss58Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse private key URI: %w", err)
+	}
+
+	return keypair, nil
+}
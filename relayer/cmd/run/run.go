@@ -1,6 +1,7 @@
 package run
 
 import (
+	"github.com/snowfork/snowbridge/relayer/cmd/run/all"
 	"github.com/snowfork/snowbridge/relayer/cmd/run/beefy"
 	"github.com/snowfork/snowbridge/relayer/cmd/run/ethereum"
 	"github.com/snowfork/snowbridge/relayer/cmd/run/parachain"
@@ -17,6 +18,7 @@ func Command() *cobra.Command {
 	cmd.AddCommand(beefy.Command())
 	cmd.AddCommand(parachain.Command())
 	cmd.AddCommand(ethereum.Command())
+	cmd.AddCommand(all.Command())
 
 	return cmd
 }
@@ -0,0 +1,118 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ethereumConfigFile  string
+	parachainConfigFile string
+	beefyConfigFile     string
+	outputJSON          bool
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report the state of the bridge",
+		Long: `Report the state of the bridge, as seen by the relays with the given configuration files.
+
+The ethereum relay configuration is used to compare the Ethereum outbound channel nonces
+with the parachain inbound channel nonces, and the Ethereum light client on the parachain
+with the Ethereum head. The parachain relay configuration is used to compare the parachain
+outbound channel nonces with the Ethereum inbound channel nonces. The beefy relay configuration
+is used to compare the BEEFY light client on Ethereum with the relay chain finalized head.`,
+		Args:    cobra.ExactArgs(0),
+		Example: "snowbridge-relay status --ethereum-config ethereum-relay.json --parachain-config parachain-relay.json --beefy-config beefy-relay.json",
+		RunE:    run,
+	}
+
+	cmd.Flags().StringVar(&ethereumConfigFile, "ethereum-config", "", "Path to ethereum relay configuration file")
+	cmd.Flags().StringVar(&parachainConfigFile, "parachain-config", "", "Path to parachain relay configuration file")
+	cmd.Flags().StringVar(&beefyConfigFile, "beefy-config", "", "Path to beefy relay configuration file")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Print the report as JSON")
+
+	return cmd
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	if ethereumConfigFile == "" && parachainConfigFile == "" && beefyConfigFile == "" {
+		return fmt.Errorf("at least one relay configuration file is required")
+	}
+
+	ctx := context.Background()
+	var report Report
+
+	if ethereumConfigFile != "" {
+		err := report.addEthereumRelay(ctx, ethereumConfigFile)
+		if err != nil {
+			return fmt.Errorf("ethereum relay: %w", err)
+		}
+	}
+
+	if parachainConfigFile != "" {
+		err := report.addParachainRelay(ctx, parachainConfigFile)
+		if err != nil {
+			return fmt.Errorf("parachain relay: %w", err)
+		}
+	}
+
+	if beefyConfigFile != "" {
+		err := report.addBeefyRelay(ctx, beefyConfigFile)
+		if err != nil {
+			return fmt.Errorf("beefy relay: %w", err)
+		}
+	}
+
+	if outputJSON {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	return report.print()
+}
+
+func (r *Report) print() error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	if len(r.Channels) > 0 {
+		fmt.Fprintln(w, "DIRECTION\tCHANNEL\tOUTBOUND NONCE\tINBOUND NONCE\tPENDING")
+		for _, channel := range r.Channels {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n",
+				channel.Direction,
+				channel.Channel,
+				channel.OutboundNonce,
+				channel.InboundNonce,
+				channel.Pending,
+			)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.LightClients) > 0 {
+		fmt.Fprintln(w, "LIGHT CLIENT\tCHAIN\tLATEST BLOCK\tHEAD\tLAG (BLOCKS)\tLAG (TIME)")
+		for _, client := range r.LightClients {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n",
+				client.LightClient,
+				client.Chain,
+				client.LatestBlock,
+				client.HeadBlock,
+				client.LagBlocks,
+				(time.Duration(client.LagSeconds) * time.Second).String(),
+			)
+		}
+	}
+
+	return w.Flush()
+}
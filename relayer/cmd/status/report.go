@@ -0,0 +1,289 @@
+package status
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
+	relayconfig "github.com/snowfork/snowbridge/relayer/config"
+	"github.com/snowfork/snowbridge/relayer/contracts/basic"
+	"github.com/snowfork/snowbridge/relayer/contracts/beefylightclient"
+	"github.com/snowfork/snowbridge/relayer/contracts/incentivized"
+	"github.com/snowfork/snowbridge/relayer/relays/beefy"
+	ethereumrelay "github.com/snowfork/snowbridge/relayer/relays/ethereum"
+	parachainrelay "github.com/snowfork/snowbridge/relayer/relays/parachain"
+)
+
+const (
+	ethereumToParachain = "ethereum-to-parachain"
+	parachainToEthereum = "parachain-to-ethereum"
+)
+
+type Report struct {
+	Channels     []ChannelStatus     `json:"channels"`
+	LightClients []LightClientStatus `json:"lightClients"`
+}
+
+// ChannelStatus compares the nonce of the last message sent by an outbound channel with the
+// nonce of the last message accepted by the matching inbound channel
+type ChannelStatus struct {
+	Direction     string `json:"direction"`
+	Channel       string `json:"channel"`
+	OutboundNonce uint64 `json:"outboundNonce"`
+	InboundNonce  uint64 `json:"inboundNonce"`
+	Pending       uint64 `json:"pending"`
+}
+
+// LightClientStatus compares the latest block known to a light client with the head of the chain it follows
+type LightClientStatus struct {
+	LightClient string `json:"lightClient"`
+	Chain       string `json:"chain"`
+	LatestBlock uint64 `json:"latestBlock"`
+	HeadBlock   uint64 `json:"headBlock"`
+	LagBlocks   uint64 `json:"lagBlocks"`
+	LagSeconds  uint64 `json:"lagSeconds"`
+}
+
+func newLightClientStatus(lightClient, chain string, latest, head, latestTime, headTime uint64) LightClientStatus {
+	return LightClientStatus{
+		LightClient: lightClient,
+		Chain:       chain,
+		LatestBlock: latest,
+		HeadBlock:   head,
+		LagBlocks:   saturatingSub(head, latest),
+		LagSeconds:  saturatingSub(headTime, latestTime),
+	}
+}
+
+func (r *Report) addChannel(direction, channel string, outboundNonce, inboundNonce uint64) {
+	r.Channels = append(r.Channels, ChannelStatus{
+		Direction:     direction,
+		Channel:       channel,
+		OutboundNonce: outboundNonce,
+		InboundNonce:  inboundNonce,
+		Pending:       saturatingSub(outboundNonce, inboundNonce),
+	})
+}
+
+// Compares the Ethereum outbound channels with the parachain inbound channels, and the Ethereum
+// light client on the parachain with the Ethereum head
+func (r *Report) addEthereumRelay(ctx context.Context, configFile string) error {
+	var config ethereumrelay.Config
+	err := relayconfig.ReadFile(configFile, &config)
+	if err != nil {
+		return err
+	}
+
+	ethconn := ethereum.NewConnection(config.Source.Ethereum.Endpoint, nil)
+	err = ethconn.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer ethconn.Close()
+
+	paraconn := parachain.NewConnection(config.Sink.Parachain.Endpoint, nil)
+	err = paraconn.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer paraconn.Close()
+
+	basicOutbound, err := basic.NewBasicOutboundChannel(
+		common.HexToAddress(config.Source.Contracts.BasicOutboundChannel), ethconn.GetClient())
+	if err != nil {
+		return err
+	}
+	basicNonce, err := basicOutbound.Nonce(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+
+	incentivizedOutbound, err := incentivized.NewIncentivizedOutboundChannel(
+		common.HexToAddress(config.Source.Contracts.IncentivizedOutboundChannel), ethconn.GetClient())
+	if err != nil {
+		return err
+	}
+	incentivizedNonce, err := incentivizedOutbound.Nonce(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+
+	inboundNonces, err := ethereumrelay.QueryInboundNonces(paraconn)
+	if err != nil {
+		return err
+	}
+
+	r.addChannel(ethereumToParachain, "basic", basicNonce, inboundNonces.Basic)
+	r.addChannel(ethereumToParachain, "incentivized", incentivizedNonce, inboundNonces.Incentivized)
+
+	storageKey, err := types.CreateStorageKey(paraconn.Metadata(), "EthereumLightClient", "FinalizedBlock", nil, nil)
+	if err != nil {
+		return err
+	}
+	var finalized ethereum.HeaderID
+	_, err = paraconn.API().RPC.State.GetStorageLatest(storageKey, &finalized)
+	if err != nil {
+		return err
+	}
+
+	finalizedHeader, err := ethconn.GetClient().HeaderByHash(ctx, common.Hash(finalized.Hash))
+	if err != nil {
+		return fmt.Errorf("fetch finalized header %s: %w", common.Hash(finalized.Hash).Hex(), err)
+	}
+	head, err := ethconn.GetClient().HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	r.LightClients = append(r.LightClients, newLightClientStatus(
+		"EthereumLightClient.FinalizedBlock", "ethereum",
+		uint64(finalized.Number), head.Number.Uint64(),
+		finalizedHeader.Time, head.Time,
+	))
+
+	return nil
+}
+
+// Compares the parachain outbound channels with the Ethereum inbound channels
+func (r *Report) addParachainRelay(ctx context.Context, configFile string) error {
+	var config parachainrelay.Config
+	err := relayconfig.ReadFile(configFile, &config)
+	if err != nil {
+		return err
+	}
+
+	ethconn := ethereum.NewConnection(config.Sink.Ethereum.Endpoint, nil)
+	err = ethconn.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer ethconn.Close()
+
+	paraconn := parachain.NewConnection(config.Source.Parachain.Endpoint, nil)
+	err = paraconn.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer paraconn.Close()
+
+	channels, err := parachainrelay.NewChannels(ethconn, config.Sink.Contracts.InboundChannels)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range channels.IDs() {
+		channel := channels[id]
+
+		inboundNonce, err := channel.InboundNonce(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return err
+		}
+
+		storageKey, err := types.CreateStorageKey(paraconn.Metadata(), channel.OutboundModule(), "Nonce", nil, nil)
+		if err != nil {
+			return err
+		}
+		var outboundNonce types.U64
+		_, err = paraconn.API().RPC.State.GetStorageLatest(storageKey, &outboundNonce)
+		if err != nil {
+			return err
+		}
+
+		r.addChannel(parachainToEthereum, channel.Name(), uint64(outboundNonce), inboundNonce)
+	}
+
+	return nil
+}
+
+// Compares the BEEFY light client on Ethereum with the relay chain finalized head
+func (r *Report) addBeefyRelay(ctx context.Context, configFile string) error {
+	var config beefy.Config
+	err := relayconfig.ReadFile(configFile, &config)
+	if err != nil {
+		return err
+	}
+
+	ethconn := ethereum.NewConnection(config.Sink.Ethereum.Endpoint, nil)
+	err = ethconn.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer ethconn.Close()
+
+	relaychainConn := relaychain.NewConnection(config.Source.Polkadot.Endpoint)
+	err = relaychainConn.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer relaychainConn.Close()
+
+	lightClient, err := beefylightclient.NewContract(
+		common.HexToAddress(config.Sink.Contracts.BeefyLightClient), ethconn.GetClient())
+	if err != nil {
+		return err
+	}
+	latestBeefyBlock, err := lightClient.LatestBeefyBlock(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+
+	api := relaychainConn.API()
+	latestHash, err := api.RPC.Chain.GetBlockHash(latestBeefyBlock)
+	if err != nil {
+		return err
+	}
+	finalizedHash, err := api.RPC.Chain.GetFinalizedHead()
+	if err != nil {
+		return err
+	}
+	finalizedHeader, err := api.RPC.Chain.GetHeader(finalizedHash)
+	if err != nil {
+		return err
+	}
+
+	latestTime, err := queryTimestamp(relaychainConn, latestHash)
+	if err != nil {
+		return err
+	}
+	finalizedTime, err := queryTimestamp(relaychainConn, finalizedHash)
+	if err != nil {
+		return err
+	}
+
+	r.LightClients = append(r.LightClients, newLightClientStatus(
+		"BeefyLightClient.LatestBeefyBlock", "relaychain",
+		latestBeefyBlock, uint64(finalizedHeader.Number),
+		latestTime, finalizedTime,
+	))
+
+	return nil
+}
+
+// Returns the timestamp in seconds of the relay chain block with the given hash
+func queryTimestamp(conn *relaychain.Connection, blockHash types.Hash) (uint64, error) {
+	storageKey, err := types.CreateStorageKey(conn.Metadata(), "Timestamp", "Now", nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var millis types.U64
+	_, err = conn.API().RPC.State.GetStorage(storageKey, &millis, blockHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(millis) / 1000, nil
+}
+
+func saturatingSub(l uint64, r uint64) uint64 {
+	if l < r {
+		return 0
+	}
+	return l - r
+}
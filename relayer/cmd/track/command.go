@@ -0,0 +1,230 @@
+package track
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	relayconfig "github.com/snowfork/snowbridge/relayer/config"
+	ethereumrelay "github.com/snowfork/snowbridge/relayer/relays/ethereum"
+	parachainrelay "github.com/snowfork/snowbridge/relayer/relays/parachain"
+)
+
+var (
+	ethereumConfigFile  string
+	parachainConfigFile string
+	txHash              string
+	direction           string
+	channel             string
+	nonce               uint64
+	searchDepth         uint64
+	outputJSON          bool
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "track",
+		Short: "Trace a message through the bridge",
+		Long: `Trace a message through the bridge, reporting each stage of its delivery and the stage
+at which it is stuck.
+
+A message is identified either by the Ethereum transaction which sent it, or by its direction,
+channel and nonce. Messages from Ethereum to the parachain are traced using the ethereum relay
+configuration through these stages:
+
+  sent             the outbound channel on Ethereum emits a Message event
+  header-imported  the parachain finalizes the Ethereum block holding the event
+  dispatched       the inbound channel on the parachain accepts the message
+
+Messages from the parachain to Ethereum are traced using the parachain relay configuration
+through these stages:
+
+  accepted         the outbound channel on the parachain accepts the message
+  committed        the outbound channel commits to the message in a block digest
+  beefy-root       the BEEFY light client on Ethereum has a root covering that block
+  delivered        the inbound channel on Ethereum dispatches the message
+
+Stages on the parachain and relay chain are located by searching their history, which requires
+archive nodes for older messages. Events on Ethereum are searched for in the most recent
+--search-depth blocks.`,
+		Args: cobra.ExactArgs(0),
+		Example: `snowbridge-relay track --ethereum-config ethereum-relay.json --tx 0x5c4a...
+snowbridge-relay track --parachain-config parachain-relay.json --direction parachain-to-ethereum --channel basic --nonce 42`,
+		RunE: run,
+	}
+
+	cmd.Flags().StringVar(&ethereumConfigFile, "ethereum-config", "", "Path to ethereum relay configuration file")
+	cmd.Flags().StringVar(&parachainConfigFile, "parachain-config", "", "Path to parachain relay configuration file")
+	cmd.Flags().StringVar(&txHash, "tx", "", "Hash of the Ethereum transaction which sent the message")
+	cmd.Flags().StringVar(&direction, "direction", "", "Direction of the message (ethereum-to-parachain or parachain-to-ethereum)")
+	cmd.Flags().StringVar(&channel, "channel", "", "Channel of the message (basic or incentivized)")
+	cmd.Flags().Uint64Var(&nonce, "nonce", 0, "Nonce of the message")
+	cmd.Flags().Uint64Var(&searchDepth, "search-depth", 100000, "Number of recent Ethereum blocks to search for events")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Print the traces as JSON")
+
+	return cmd
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	var traces []*Trace
+	var err error
+	switch {
+	case txHash != "":
+		traces, err = traceTransaction(ctx)
+	case direction == ethereumToParachain:
+		traces, err = traceEthereumMessage(ctx)
+	case direction == parachainToEthereum:
+		traces, err = traceParachainMessage(ctx)
+	case direction == "":
+		return fmt.Errorf("either --tx or --direction, --channel and --nonce are required")
+	default:
+		return fmt.Errorf("unknown direction %q", direction)
+	}
+	if err != nil {
+		return err
+	}
+
+	if outputJSON {
+		b, err := json.MarshalIndent(traces, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	for i, trace := range traces {
+		if i > 0 {
+			fmt.Println()
+		}
+		err := trace.print()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Traces every message sent by the Ethereum transaction
+func traceTransaction(ctx context.Context) ([]*Trace, error) {
+	tracker, err := ethereumTrackerFromFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tracker.close()
+
+	messages, err := tracker.messagesInTransaction(common.HexToHash(txHash))
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("transaction %s did not send any messages", txHash)
+	}
+
+	var traces []*Trace
+	for i := range messages {
+		trace, err := tracker.trace(messages[i].channel, messages[i].nonce, &messages[i])
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, trace)
+	}
+	return traces, nil
+}
+
+func traceEthereumMessage(ctx context.Context) ([]*Trace, error) {
+	tracker, err := ethereumTrackerFromFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tracker.close()
+
+	trace, err := tracker.trace(channel, nonce, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []*Trace{trace}, nil
+}
+
+func traceParachainMessage(ctx context.Context) ([]*Trace, error) {
+	if parachainConfigFile == "" {
+		return nil, fmt.Errorf("--parachain-config is required for messages from the parachain")
+	}
+
+	var config parachainrelay.Config
+	err := relayconfig.ReadFile(parachainConfigFile, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker, err := newParachainTracker(ctx, &config, searchDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer tracker.close()
+
+	trace, err := tracker.trace(channel, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return []*Trace{trace}, nil
+}
+
+func ethereumTrackerFromFlags(ctx context.Context) (*ethereumTracker, error) {
+	if ethereumConfigFile == "" {
+		return nil, fmt.Errorf("--ethereum-config is required for messages from Ethereum")
+	}
+
+	var config ethereumrelay.Config
+	err := relayconfig.ReadFile(ethereumConfigFile, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEthereumTracker(ctx, &config, searchDepth)
+}
+
+func (t *Trace) print() error {
+	fmt.Printf("Message %d on the %s channel, %s\n\n", t.Nonce, t.Channel, t.Direction)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tSTATUS\tCHAIN\tBLOCK\tTIME\tTX/EXTRINSIC\tDETAIL")
+	for _, stage := range t.Stages {
+		block := ""
+		if stage.BlockNumber != 0 {
+			block = fmt.Sprint(stage.BlockNumber)
+		}
+		timestamp := ""
+		if stage.Timestamp != nil {
+			timestamp = stage.Timestamp.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			stage.Name,
+			stage.Status,
+			stage.Chain,
+			block,
+			timestamp,
+			stage.TxHash,
+			stage.Detail,
+		)
+	}
+	err := w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if t.StuckAt == "" {
+		fmt.Println("\nDelivered")
+	} else {
+		fmt.Printf("\nStuck at: %s\n", t.StuckAt)
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+package track
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/contracts/basic"
+	"github.com/snowfork/snowbridge/relayer/contracts/incentivized"
+	ethereumrelay "github.com/snowfork/snowbridge/relayer/relays/ethereum"
+)
+
+// outboundMessage is a Message event emitted by an outbound channel on Ethereum
+type outboundMessage struct {
+	channel string
+	nonce   uint64
+	log     etypes.Log
+}
+
+// outboundChannel wraps the outbound channel contracts so that they can be searched in the same way
+type outboundChannel struct {
+	name           string
+	address        common.Address
+	inboundModule  string
+	nonce          func(opts *bind.CallOpts) (uint64, error)
+	filterMessages func(opts *bind.FilterOpts) ([]outboundMessage, error)
+	parseMessage   func(log etypes.Log) (uint64, error)
+}
+
+// ethereumTracker traces messages sent from Ethereum to the parachain
+type ethereumTracker struct {
+	ctx         context.Context
+	ethconn     *ethereum.Connection
+	paraconn    *parachain.Connection
+	channels    []outboundChannel
+	searchDepth uint64
+}
+
+func newEthereumTracker(ctx context.Context, config *ethereumrelay.Config, searchDepth uint64) (*ethereumTracker, error) {
+	ethconn := ethereum.NewConnection(config.Source.Ethereum.Endpoint, nil)
+	err := ethconn.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paraconn := parachain.NewConnection(config.Sink.Parachain.Endpoint, nil)
+	err = paraconn.Connect(ctx)
+	if err != nil {
+		ethconn.Close()
+		return nil, err
+	}
+
+	basicAddress := common.HexToAddress(config.Source.Contracts.BasicOutboundChannel)
+	basicOutbound, err := basic.NewBasicOutboundChannel(basicAddress, ethconn.GetClient())
+	if err != nil {
+		ethconn.Close()
+		return nil, err
+	}
+
+	incentivizedAddress := common.HexToAddress(config.Source.Contracts.IncentivizedOutboundChannel)
+	incentivizedOutbound, err := incentivized.NewIncentivizedOutboundChannel(incentivizedAddress, ethconn.GetClient())
+	if err != nil {
+		ethconn.Close()
+		return nil, err
+	}
+
+	channels := []outboundChannel{
+		{
+			name:          "basic",
+			address:       basicAddress,
+			inboundModule: "BasicInboundModule",
+			nonce:         basicOutbound.Nonce,
+			filterMessages: func(opts *bind.FilterOpts) ([]outboundMessage, error) {
+				iter, err := basicOutbound.FilterMessage(opts)
+				if err != nil {
+					return nil, err
+				}
+				defer iter.Close()
+
+				var messages []outboundMessage
+				for iter.Next() {
+					messages = append(messages, outboundMessage{"basic", iter.Event.Nonce, iter.Event.Raw})
+				}
+				return messages, iter.Error()
+			},
+			parseMessage: func(log etypes.Log) (uint64, error) {
+				event, err := basicOutbound.ParseMessage(log)
+				if err != nil {
+					return 0, err
+				}
+				return event.Nonce, nil
+			},
+		},
+		{
+			name:          "incentivized",
+			address:       incentivizedAddress,
+			inboundModule: "IncentivizedInboundModule",
+			nonce:         incentivizedOutbound.Nonce,
+			filterMessages: func(opts *bind.FilterOpts) ([]outboundMessage, error) {
+				iter, err := incentivizedOutbound.FilterMessage(opts)
+				if err != nil {
+					return nil, err
+				}
+				defer iter.Close()
+
+				var messages []outboundMessage
+				for iter.Next() {
+					messages = append(messages, outboundMessage{"incentivized", iter.Event.Nonce, iter.Event.Raw})
+				}
+				return messages, iter.Error()
+			},
+			parseMessage: func(log etypes.Log) (uint64, error) {
+				event, err := incentivizedOutbound.ParseMessage(log)
+				if err != nil {
+					return 0, err
+				}
+				return event.Nonce, nil
+			},
+		},
+	}
+
+	return &ethereumTracker{
+		ctx:         ctx,
+		ethconn:     ethconn,
+		paraconn:    paraconn,
+		channels:    channels,
+		searchDepth: searchDepth,
+	}, nil
+}
+
+func (t *ethereumTracker) close() {
+	t.ethconn.Close()
+	t.paraconn.Close()
+}
+
+func (t *ethereumTracker) channel(name string) (*outboundChannel, error) {
+	for i := range t.channels {
+		if t.channels[i].name == name {
+			return &t.channels[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown channel %q", name)
+}
+
+// Returns the messages sent by the transaction with the given hash
+func (t *ethereumTracker) messagesInTransaction(txHash common.Hash) ([]outboundMessage, error) {
+	receipt, err := t.ethconn.GetClient().TransactionReceipt(t.ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("fetch receipt for transaction %s: %w", txHash.Hex(), err)
+	}
+
+	var messages []outboundMessage
+	for _, log := range receipt.Logs {
+		for _, channel := range t.channels {
+			if log.Address != channel.address {
+				continue
+			}
+			nonce, err := channel.parseMessage(*log)
+			if err != nil {
+				// Not a Message event
+				continue
+			}
+			messages = append(messages, outboundMessage{channel.name, nonce, *log})
+		}
+	}
+
+	return messages, nil
+}
+
+// Searches the most recent blocks for the message with the given nonce, returning nil if it isn't found
+func (t *ethereumTracker) findMessage(channel *outboundChannel, nonce uint64) (*outboundMessage, error) {
+	head, err := t.ethconn.GetClient().BlockNumber(t.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *outboundMessage
+	err = scanBackwards(head, t.searchDepth, func(start, end uint64) (bool, error) {
+		messages, err := channel.filterMessages(&bind.FilterOpts{Start: start, End: &end, Context: t.ctx})
+		if err != nil {
+			return false, err
+		}
+
+		reachedNonce := false
+		for i := range messages {
+			if messages[i].nonce == nonce {
+				found = &messages[i]
+			}
+			if messages[i].nonce <= nonce {
+				reachedNonce = true
+			}
+		}
+		return reachedNonce, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// Traces the message with the given nonce. The Message event is searched for if it isn't given.
+func (t *ethereumTracker) trace(channelName string, nonce uint64, message *outboundMessage) (*Trace, error) {
+	channel, err := t.channel(channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := &Stage{Name: "sent", Chain: "ethereum"}
+	imported := &Stage{Name: "header-imported", Chain: "parachain"}
+	dispatched := &Stage{Name: "dispatched", Chain: "parachain"}
+	trace := newTrace(ethereumToParachain, channel.name, nonce, sent, imported, dispatched)
+
+	if message == nil {
+		message, err = t.findMessage(channel, nonce)
+		if err != nil {
+			sent.unknown(err)
+			return trace.finish(), nil
+		}
+	}
+
+	if message == nil {
+		outboundNonce, err := channel.nonce(&bind.CallOpts{Context: t.ctx})
+		if err != nil {
+			sent.unknown(err)
+		} else if nonce > outboundNonce {
+			sent.Detail = fmt.Sprintf("outbound channel nonce is %d", outboundNonce)
+		} else {
+			sent.Status = StatusUnknown
+			sent.Detail = fmt.Sprintf("Message event not found in the last %d blocks", t.searchDepth)
+		}
+		return trace.finish(), nil
+	}
+
+	sent.Status = StatusDone
+	sent.setEthereumBlock(t.ctx, t.ethconn, message.log.BlockNumber)
+	sent.TxHash = message.log.TxHash.Hex()
+
+	t.traceHeaderImport(imported, message.log.BlockNumber)
+	if !imported.done() {
+		return trace.finish(), nil
+	}
+
+	t.traceDispatch(dispatched, channel, nonce, &message.log)
+	return trace.finish(), nil
+}
+
+// The message can be dispatched once the parachain has finalized the Ethereum block which includes it
+func (t *ethereumTracker) traceHeaderImport(stage *Stage, ethereumBlock uint64) {
+	finalizedBlock := func(blockHash types.Hash) (uint64, error) {
+		var finalized ethereum.HeaderID
+		err := readStorage(t.paraconn, blockHash, "EthereumLightClient", "FinalizedBlock", &finalized)
+		return uint64(finalized.Number), err
+	}
+
+	head, headHash, err := finalizedHead(t.paraconn)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+
+	latest, err := finalizedBlock(headHash)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+	if latest < ethereumBlock {
+		stage.Detail = fmt.Sprintf("parachain has finalized Ethereum block %d", latest)
+		return
+	}
+
+	stage.Status = StatusDone
+	number, blockHash, err := findFirstBlock(t.paraconn.API().RPC.Chain, head, func(blockHash types.Hash) (bool, error) {
+		finalized, err := finalizedBlock(blockHash)
+		return finalized >= ethereumBlock, err
+	})
+	if err != nil {
+		stage.Detail = fmt.Sprintf("import block not found: %s", err)
+		return
+	}
+
+	stage.setSubstrateBlock(t.paraconn, number, blockHash)
+	// Any relayer may have imported the header, so the extrinsic is a best guess
+	stage.TxHash, err = findExtrinsic(t.paraconn, blockHash, nil, "EthereumLightClient.import_header", "Utility.batch_all")
+	if err != nil {
+		stage.Detail = fmt.Sprintf("import extrinsic not found: %s", err)
+	}
+}
+
+func (t *ethereumTracker) traceDispatch(stage *Stage, channel *outboundChannel, nonce uint64, log *etypes.Log) {
+	inboundNonce := func(blockHash types.Hash) (uint64, error) {
+		var inboundNonce types.U64
+		err := readStorage(t.paraconn, blockHash, channel.inboundModule, "Nonce", &inboundNonce)
+		return uint64(inboundNonce), err
+	}
+
+	head, headHash, err := finalizedHead(t.paraconn)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+
+	latest, err := inboundNonce(headHash)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+	if latest < nonce {
+		stage.Detail = fmt.Sprintf("inbound channel nonce is %d", latest)
+		return
+	}
+
+	stage.Status = StatusDone
+	number, blockHash, err := findFirstBlock(t.paraconn.API().RPC.Chain, head, func(blockHash types.Hash) (bool, error) {
+		accepted, err := inboundNonce(blockHash)
+		return accepted >= nonce, err
+	})
+	if err != nil {
+		stage.Detail = fmt.Sprintf("dispatch block not found: %s", err)
+		return
+	}
+
+	stage.setSubstrateBlock(t.paraconn, number, blockHash)
+
+	// The message submitted to the parachain carries the RLP-encoded Message event
+	var data bytes.Buffer
+	err = log.EncodeRLP(&data)
+	if err != nil {
+		stage.Detail = fmt.Sprintf("encode Message event: %s", err)
+		return
+	}
+	stage.TxHash, err = findExtrinsic(t.paraconn, blockHash, data.Bytes(), channel.inboundModule+".submit", "Utility.batch_all")
+	if err != nil {
+		stage.Detail = fmt.Sprintf("dispatch extrinsic not found: %s", err)
+	}
+}
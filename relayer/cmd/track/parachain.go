@@ -0,0 +1,382 @@
+package track
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
+	"github.com/snowfork/snowbridge/relayer/contracts/beefylightclient"
+	parachainrelay "github.com/snowfork/snowbridge/relayer/relays/parachain"
+)
+
+// Number of parachain blocks after the message was accepted which are searched for its commitment
+const commitmentSearchDepth = 256
+
+// parachainTracker traces messages sent from the parachain to Ethereum
+type parachainTracker struct {
+	ctx            context.Context
+	ethconn        *ethereum.Connection
+	paraconn       *parachain.Connection
+	relaychainConn *relaychain.Connection
+	channels       parachainrelay.Channels
+	lightClient    *beefylightclient.Contract
+	paraID         uint32
+	searchDepth    uint64
+}
+
+func newParachainTracker(ctx context.Context, config *parachainrelay.Config, searchDepth uint64) (*parachainTracker, error) {
+	t := parachainTracker{
+		ctx:     ctx,
+		ethconn: ethereum.NewConnection(config.Sink.Ethereum.Endpoint, nil),
+		paraconn: parachain.NewConnection(
+			config.Source.Parachain.Endpoint,
+			nil,
+			config.Source.Parachain.FallbackEndpoints...,
+		),
+		relaychainConn: relaychain.NewConnection(config.Source.Polkadot.Endpoint),
+		searchDepth:    searchDepth,
+	}
+
+	err := t.ethconn.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.connect(config)
+	if err != nil {
+		t.ethconn.Close()
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (t *parachainTracker) connect(config *parachainrelay.Config) error {
+	err := t.paraconn.Connect(t.ctx)
+	if err != nil {
+		return err
+	}
+
+	err = t.relaychainConn.Connect(t.ctx)
+	if err != nil {
+		return err
+	}
+
+	t.channels, err = parachainrelay.NewChannels(t.ethconn, config.Sink.Contracts.InboundChannels)
+	if err != nil {
+		return err
+	}
+
+	t.lightClient, err = beefylightclient.NewContract(
+		common.HexToAddress(config.Source.Contracts.BeefyLightClient), t.ethconn.GetClient())
+	if err != nil {
+		return err
+	}
+
+	storageKey, err := types.CreateStorageKey(t.paraconn.Metadata(), "ParachainInfo", "ParachainId", nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = t.paraconn.API().RPC.State.GetStorageLatest(storageKey, &t.paraID)
+	return err
+}
+
+func (t *parachainTracker) close() {
+	t.ethconn.Close()
+	t.paraconn.Close()
+	t.relaychainConn.Close()
+}
+
+func (t *parachainTracker) channel(name string) (parachainrelay.Channel, error) {
+	for _, channel := range t.channels {
+		if channel.Name() == name {
+			return channel, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown channel %q", name)
+}
+
+// Traces the message with the given nonce
+func (t *parachainTracker) trace(channelName string, nonce uint64) (*Trace, error) {
+	channel, err := t.channel(channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := &Stage{Name: "accepted", Chain: "parachain"}
+	committed := &Stage{Name: "committed", Chain: "parachain"}
+	beefyRoot := &Stage{Name: "beefy-root", Chain: "ethereum"}
+	delivered := &Stage{Name: "delivered", Chain: "ethereum"}
+	trace := newTrace(parachainToEthereum, channel.Name(), nonce, accepted, committed, beefyRoot, delivered)
+
+	t.traceAccepted(accepted, channel, nonce)
+	if !accepted.done() {
+		return trace.finish(), nil
+	}
+
+	t.traceCommitment(committed, channel, nonce, accepted.BlockNumber)
+	if !committed.done() {
+		return trace.finish(), nil
+	}
+
+	t.traceBeefyRoot(beefyRoot, committed.BlockNumber)
+	if !beefyRoot.done() {
+		return trace.finish(), nil
+	}
+
+	t.traceDelivery(delivered, channel, nonce)
+	return trace.finish(), nil
+}
+
+// The outbound channel on the parachain accepts the message in the block in which its nonce is reached
+func (t *parachainTracker) traceAccepted(stage *Stage, channel parachainrelay.Channel, nonce uint64) {
+	outboundNonce := func(blockHash types.Hash) (uint64, error) {
+		var outboundNonce types.U64
+		err := readStorage(t.paraconn, blockHash, channel.OutboundModule(), "Nonce", &outboundNonce)
+		return uint64(outboundNonce), err
+	}
+
+	head, headHash, err := finalizedHead(t.paraconn)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+
+	latest, err := outboundNonce(headHash)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+	if latest < nonce {
+		stage.Detail = fmt.Sprintf("outbound channel nonce at the finalized head is %d", latest)
+		return
+	}
+
+	number, blockHash, err := findFirstBlock(t.paraconn.API().RPC.Chain, head, func(blockHash types.Hash) (bool, error) {
+		sent, err := outboundNonce(blockHash)
+		return sent >= nonce, err
+	})
+	if err != nil {
+		// Later stages are searched for from this block
+		stage.unknown(err)
+		return
+	}
+
+	stage.Status = StatusDone
+	stage.setSubstrateBlock(t.paraconn, number, blockHash)
+}
+
+// The outbound channel commits to its queued messages in the digest of a later block
+func (t *parachainTracker) traceCommitment(stage *Stage, channel parachainrelay.Channel, nonce uint64, acceptedBlock uint64) {
+	head, _, err := finalizedHead(t.paraconn)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+
+	api := t.paraconn.API()
+	for number := acceptedBlock; number <= head && number < acceptedBlock+commitmentSearchDepth; number++ {
+		blockHash, err := api.RPC.Chain.GetBlockHash(number)
+		if err != nil {
+			stage.unknown(err)
+			return
+		}
+		header, err := api.RPC.Chain.GetHeader(blockHash)
+		if err != nil {
+			stage.unknown(err)
+			return
+		}
+
+		digestItems, err := parachain.ExtractAuxiliaryDigestItems(header.Digest)
+		if err != nil {
+			stage.unknown(err)
+			return
+		}
+
+		for _, digestItem := range digestItems {
+			if !digestItem.IsCommitment || digestItem.AsCommitment.ChannelID != channel.ID() {
+				continue
+			}
+
+			stage.Detail = fmt.Sprintf("commitment %s", digestItem.AsCommitment.Hash.Hex())
+
+			// The first commitment after the message was accepted includes it, which is confirmed
+			// from the commitment data if a node still holds it
+			data, err := t.paraconn.GetDataForDigestItem(&digestItem)
+			if err == nil {
+				nonces, err := channel.DecodeNonces(data)
+				if err == nil && !containsNonce(nonces, nonce) {
+					continue
+				}
+			}
+
+			stage.Status = StatusDone
+			stage.setSubstrateBlock(t.paraconn, number, blockHash)
+			return
+		}
+	}
+
+	stage.Detail = ""
+	if head < acceptedBlock+commitmentSearchDepth {
+		return
+	}
+	stage.Status = StatusUnknown
+	stage.Detail = fmt.Sprintf("commitment not found in the %d blocks after the message was accepted", commitmentSearchDepth)
+}
+
+// The commitment can be delivered once the BEEFY light client on Ethereum has a root for a relay
+// chain block which includes the parachain block
+func (t *parachainTracker) traceBeefyRoot(stage *Stage, commitmentBlock uint64) {
+	latestBeefyBlock, err := t.lightClient.LatestBeefyBlock(&bind.CallOpts{Context: t.ctx})
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+
+	paraHead := func(blockHash types.Hash) (uint64, error) {
+		header, err := t.relaychainConn.FetchFinalizedParaHead(blockHash, t.paraID)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(header.Number), nil
+	}
+
+	latestHash, err := t.relaychainConn.API().RPC.Chain.GetBlockHash(latestBeefyBlock)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+	latestParaHead, err := paraHead(latestHash)
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+	if latestParaHead < commitmentBlock {
+		stage.Detail = fmt.Sprintf(
+			"light client is at relay chain block %d, which includes parachain block %d",
+			latestBeefyBlock, latestParaHead,
+		)
+		return
+	}
+
+	stage.Status = StatusDone
+	relayBlock, _, err := findFirstBlock(t.relaychainConn.API().RPC.Chain, latestBeefyBlock, func(blockHash types.Hash) (bool, error) {
+		included, err := paraHead(blockHash)
+		return included >= commitmentBlock, err
+	})
+	if err != nil {
+		stage.Detail = fmt.Sprintf("relay chain block not found: %s", err)
+		return
+	}
+
+	// The earliest root for a relay chain block at or after the one including the parachain block
+	var root *beefylightclient.ContractNewMMRRoot
+	head, err := t.ethconn.GetClient().BlockNumber(t.ctx)
+	if err != nil {
+		stage.Detail = err.Error()
+		return
+	}
+	err = scanBackwards(head, t.searchDepth, func(start, end uint64) (bool, error) {
+		iter, err := t.lightClient.FilterNewMMRRoot(&bind.FilterOpts{Start: start, End: &end, Context: t.ctx})
+		if err != nil {
+			return false, err
+		}
+		defer iter.Close()
+
+		var events []*beefylightclient.ContractNewMMRRoot
+		for iter.Next() {
+			events = append(events, iter.Event)
+		}
+		if iter.Error() != nil {
+			return false, iter.Error()
+		}
+
+		for i := len(events) - 1; i >= 0; i-- {
+			if events[i].BlockNumber < relayBlock {
+				return true, nil
+			}
+			root = events[i]
+		}
+		return false, nil
+	})
+	if err != nil {
+		stage.Detail = fmt.Sprintf("relay chain block %d, NewMMRRoot event not found: %s", relayBlock, err)
+		return
+	}
+	if root == nil {
+		stage.Detail = fmt.Sprintf("relay chain block %d, NewMMRRoot event not found in the last %d blocks", relayBlock, t.searchDepth)
+		return
+	}
+
+	stage.setEthereumBlock(t.ctx, t.ethconn, root.Raw.BlockNumber)
+	stage.TxHash = root.Raw.TxHash.Hex()
+	stage.Detail = fmt.Sprintf("relay chain block %d, root for relay chain block %d", relayBlock, root.BlockNumber)
+}
+
+func (t *parachainTracker) traceDelivery(stage *Stage, channel parachainrelay.Channel, nonce uint64) {
+	inboundNonce, err := channel.InboundNonce(&bind.CallOpts{Context: t.ctx})
+	if err != nil {
+		stage.unknown(err)
+		return
+	}
+	if inboundNonce < nonce {
+		stage.Detail = fmt.Sprintf("inbound channel nonce is %d", inboundNonce)
+		return
+	}
+
+	stage.Status = StatusDone
+	head, err := t.ethconn.GetClient().BlockNumber(t.ctx)
+	if err != nil {
+		stage.Detail = err.Error()
+		return
+	}
+
+	var dispatched *parachainrelay.MessageDispatched
+	err = scanBackwards(head, t.searchDepth, func(start, end uint64) (bool, error) {
+		events, err := channel.FilterMessageDispatched(&bind.FilterOpts{Start: start, End: &end, Context: t.ctx})
+		if err != nil {
+			return false, err
+		}
+
+		reachedNonce := false
+		for i := range events {
+			if events[i].Nonce == nonce {
+				dispatched = &events[i]
+			}
+			if events[i].Nonce <= nonce {
+				reachedNonce = true
+			}
+		}
+		return reachedNonce, nil
+	})
+	if err != nil {
+		stage.Detail = fmt.Sprintf("MessageDispatched event not found: %s", err)
+		return
+	}
+	if dispatched == nil {
+		stage.Detail = fmt.Sprintf("MessageDispatched event not found in the last %d blocks", t.searchDepth)
+		return
+	}
+
+	stage.setEthereumBlock(t.ctx, t.ethconn, dispatched.BlockNumber)
+	stage.TxHash = dispatched.TxHash.Hex()
+	if !dispatched.Result {
+		stage.Status = StatusFailed
+		stage.Detail = "message was delivered but its dispatch failed"
+	}
+}
+
+func containsNonce(nonces []uint64, nonce uint64) bool {
+	for _, n := range nonces {
+		if n == nonce {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+package track
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	gsrpc "github.com/snowfork/go-substrate-rpc-client/v3"
+	"github.com/snowfork/go-substrate-rpc-client/v3/hash"
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+)
+
+const (
+	ethereumToParachain = "ethereum-to-parachain"
+	parachainToEthereum = "parachain-to-ethereum"
+)
+
+const (
+	StatusDone    = "done"
+	StatusPending = "pending"
+	StatusFailed  = "failed"
+	StatusUnknown = "unknown"
+)
+
+// Number of Ethereum blocks whose logs are fetched in a single request
+const scanWindow = 1000
+
+// Trace follows a message through the stages of its delivery
+type Trace struct {
+	Direction string   `json:"direction"`
+	Channel   string   `json:"channel"`
+	Nonce     uint64   `json:"nonce"`
+	Stages    []*Stage `json:"stages"`
+	// StuckAt is the name of the first stage which isn't done, or empty if the message was delivered
+	StuckAt string `json:"stuckAt,omitempty"`
+}
+
+// Stage is a step in the delivery of a message. The block is the one in which the stage completed.
+type Stage struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Chain       string     `json:"chain"`
+	BlockNumber uint64     `json:"blockNumber,omitempty"`
+	BlockHash   string     `json:"blockHash,omitempty"`
+	Timestamp   *time.Time `json:"timestamp,omitempty"`
+	// TxHash is the hash of the Ethereum transaction or parachain extrinsic which completed the stage.
+	// For header imports, which can't be told apart by message, it is the first import in the block.
+	TxHash string `json:"txHash,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Creates a trace in which every stage is pending
+func newTrace(direction, channel string, nonce uint64, stages ...*Stage) *Trace {
+	for _, stage := range stages {
+		stage.Status = StatusPending
+	}
+	return &Trace{
+		Direction: direction,
+		Channel:   channel,
+		Nonce:     nonce,
+		Stages:    stages,
+	}
+}
+
+func (t *Trace) finish() *Trace {
+	for _, stage := range t.Stages {
+		if stage.Status != StatusDone {
+			t.StuckAt = stage.Name
+			break
+		}
+	}
+	return t
+}
+
+func (s *Stage) done() bool {
+	return s.Status == StatusDone
+}
+
+// Marks the stage as unknown because the chain could not be queried
+func (s *Stage) unknown(err error) {
+	s.Status = StatusUnknown
+	s.Detail = err.Error()
+}
+
+func (s *Stage) setEthereumBlock(ctx context.Context, conn *ethereum.Connection, number uint64) {
+	s.BlockNumber = number
+	header, err := conn.GetClient().HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return
+	}
+	s.BlockHash = header.Hash().Hex()
+	timestamp := time.Unix(int64(header.Time), 0).UTC()
+	s.Timestamp = &timestamp
+}
+
+func (s *Stage) setSubstrateBlock(conn substrateConnection, number uint64, blockHash types.Hash) {
+	s.BlockNumber = number
+	s.BlockHash = blockHash.Hex()
+	var millis types.U64
+	err := readStorage(conn, blockHash, "Timestamp", "Now", &millis)
+	if err != nil {
+		return
+	}
+	timestamp := time.Unix(0, int64(millis)*int64(time.Millisecond)).UTC()
+	s.Timestamp = &timestamp
+}
+
+// substrateConnection is implemented by the parachain and relay chain connections
+type substrateConnection interface {
+	API() *gsrpc.SubstrateAPI
+	Metadata() *types.Metadata
+}
+
+func readStorage(conn substrateConnection, blockHash types.Hash, module, item string, value interface{}) error {
+	storageKey, err := types.CreateStorageKey(conn.Metadata(), module, item, nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = conn.API().RPC.State.GetStorage(storageKey, value, blockHash)
+	if err != nil {
+		return fmt.Errorf("read %s.%s at block %s: %w", module, item, blockHash.Hex(), err)
+	}
+	return nil
+}
+
+func finalizedHead(conn substrateConnection) (uint64, types.Hash, error) {
+	blockHash, err := conn.API().RPC.Chain.GetFinalizedHead()
+	if err != nil {
+		return 0, types.Hash{}, err
+	}
+	header, err := conn.API().RPC.Chain.GetHeader(blockHash)
+	if err != nil {
+		return 0, types.Hash{}, err
+	}
+	return uint64(header.Number), blockHash, nil
+}
+
+// blockHashes looks up the hashes of blocks by number, like the chain RPC of a substrate connection
+type blockHashes interface {
+	GetBlockHash(blockNumber uint64) (types.Hash, error)
+}
+
+// Returns the first block up to head at which the condition holds, given that it holds at head.
+// The condition must keep holding once it holds, like a nonce reaching a value.
+func findFirstBlock(chain blockHashes, head uint64, holds func(blockHash types.Hash) (bool, error)) (uint64, types.Hash, error) {
+	low, high := uint64(0), head
+	for low < high {
+		mid := low + (high-low)/2
+		blockHash, err := chain.GetBlockHash(mid)
+		if err != nil {
+			return 0, types.Hash{}, err
+		}
+		ok, err := holds(blockHash)
+		if err != nil {
+			return 0, types.Hash{}, err
+		}
+		if ok {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	blockHash, err := chain.GetBlockHash(low)
+	if err != nil {
+		return 0, types.Hash{}, err
+	}
+	return low, blockHash, nil
+}
+
+// Returns the hash of the first extrinsic in the block which makes one of the given calls and whose
+// encoding contains the given bytes, or an empty string if there is none. Without bytes to match, the
+// extrinsic found is a best guess, as the block may include the same calls from other relayers.
+func findExtrinsic(conn substrateConnection, blockHash types.Hash, contains []byte, calls ...string) (string, error) {
+	var callIndexes []types.CallIndex
+	for _, call := range calls {
+		callIndex, err := conn.Metadata().FindCallIndex(call)
+		if err != nil {
+			// Not every runtime includes every pallet
+			continue
+		}
+		callIndexes = append(callIndexes, callIndex)
+	}
+
+	block, err := conn.API().RPC.Chain.GetBlock(blockHash)
+	if err != nil {
+		return "", err
+	}
+
+	for _, extrinsic := range block.Block.Extrinsics {
+		for _, callIndex := range callIndexes {
+			if extrinsic.Method.CallIndex != callIndex {
+				continue
+			}
+
+			encoded, err := types.EncodeToBytes(extrinsic)
+			if err != nil {
+				return "", err
+			}
+			if contains != nil && !bytes.Contains(encoded, contains) {
+				continue
+			}
+			hasher, err := hash.NewBlake2b256(nil)
+			if err != nil {
+				return "", err
+			}
+			hasher.Write(encoded)
+			return types.NewHash(hasher.Sum(nil)).Hex(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// Calls visit with successively older windows of Ethereum blocks, starting from head, until visit
+// returns true or searchDepth blocks have been visited
+func scanBackwards(head, searchDepth uint64, visit func(start, end uint64) (bool, error)) error {
+	end := head
+	for searched := uint64(0); searched < searchDepth; {
+		size := uint64(scanWindow)
+		if searchDepth-searched < size {
+			size = searchDepth - searched
+		}
+		start := uint64(0)
+		if end+1 > size {
+			start = end + 1 - size
+		}
+
+		found, err := visit(start, end)
+		if err != nil || found {
+			return err
+		}
+
+		searched += end - start + 1
+		if start == 0 {
+			return nil
+		}
+		end = start - 1
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package track
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type window struct {
+	start, end uint64
+}
+
+func collectWindows(head, searchDepth uint64, stopAt uint64) ([]window, error) {
+	var windows []window
+	err := scanBackwards(head, searchDepth, func(start, end uint64) (bool, error) {
+		windows = append(windows, window{start, end})
+		return start <= stopAt && stopAt <= end, nil
+	})
+	return windows, err
+}
+
+func TestScanBackwardsHeadWithinWindow(t *testing.T) {
+	windows, err := collectWindows(500, 5000, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []window{{0, 500}}, windows)
+
+	windows, err = collectWindows(0, 5000, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []window{{0, 0}}, windows)
+}
+
+func TestScanBackwardsDepthExhausted(t *testing.T) {
+	windows, err := collectWindows(5000, 1500, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []window{{4001, 5000}, {3501, 4000}}, windows)
+
+	windows, err = collectWindows(5000, 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, windows)
+}
+
+func TestScanBackwardsStopsWhenFound(t *testing.T) {
+	windows, err := collectWindows(5000, 100000, 3200)
+	assert.NoError(t, err)
+	assert.Equal(t, []window{{4001, 5000}, {3001, 4000}}, windows)
+
+	failure := errors.New("request failed")
+	err = scanBackwards(5000, 100000, func(start, end uint64) (bool, error) {
+		return false, failure
+	})
+	assert.Equal(t, failure, err)
+}
+
+// testChain hashes each block number into the first byte of its hash
+type testChain struct {
+	queries int
+}
+
+func (c *testChain) GetBlockHash(blockNumber uint64) (types.Hash, error) {
+	c.queries++
+	return types.Hash{byte(blockNumber)}, nil
+}
+
+func holdsFrom(first uint64) func(blockHash types.Hash) (bool, error) {
+	return func(blockHash types.Hash) (bool, error) {
+		return uint64(blockHash[0]) >= first, nil
+	}
+}
+
+func TestFindFirstBlock(t *testing.T) {
+	chain := &testChain{}
+	number, blockHash, err := findFirstBlock(chain, 200, holdsFrom(37))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(37), number)
+	assert.Equal(t, types.Hash{37}, blockHash)
+	// Binary search, plus the lookup of the block found
+	assert.LessOrEqual(t, chain.queries, 9)
+}
+
+func TestFindFirstBlockHoldsAtGenesis(t *testing.T) {
+	number, blockHash, err := findFirstBlock(&testChain{}, 200, holdsFrom(0))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), number)
+	assert.Equal(t, types.Hash{0}, blockHash)
+
+	number, _, err = findFirstBlock(&testChain{}, 0, holdsFrom(0))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), number)
+}
+
+func TestFindFirstBlockHoldsOnlyAtHead(t *testing.T) {
+	number, _, err := findFirstBlock(&testChain{}, 200, holdsFrom(200))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), number)
+}
+
+func TestFindFirstBlockError(t *testing.T) {
+	failure := errors.New("storage not found")
+	_, _, err := findFirstBlock(&testChain{}, 200, func(types.Hash) (bool, error) {
+		return false, failure
+	})
+	assert.Equal(t, failure, err)
+}
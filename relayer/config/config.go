@@ -7,6 +7,17 @@ type PolkadotConfig struct {
 
 type ParachainConfig struct {
 	Endpoint string `mapstructure:"endpoint"`
+	// Nodes from which commitments are read if they are missing from the offchain storage of the main node
+	FallbackEndpoints []string `mapstructure:"fallback-endpoints"`
+	// Commitment read from each node to check that offchain indexing is enabled, when no commitment is
+	// found in recent blocks. Usually one of the first commitments made by the parachain.
+	ProbeCommitment *CommitmentConfig `mapstructure:"probe-commitment"`
+}
+
+type CommitmentConfig struct {
+	// Index of the channel in the runtime's ChannelId enum
+	ChannelID uint8  `mapstructure:"channel-id"`
+	Hash      string `mapstructure:"hash"`
 }
 
 type EthereumConfig struct {
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+// ReadFile reads a relay configuration file into config. Each relay has its own file, so a fresh viper
+// instance is used for each.
+func ReadFile(path string, config interface{}) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	if err != nil {
+		return err
+	}
+	return v.Unmarshal(config)
+}
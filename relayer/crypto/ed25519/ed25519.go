@@ -0,0 +1,95 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ed25519
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/snowfork/snowbridge/relayer/crypto"
+	"github.com/vedhavyas/go-subkey"
+	"github.com/vedhavyas/go-subkey/ed25519"
+)
+
+var _ crypto.Keypair = &Keypair{}
+
+const (
+	SeedLength      = 32
+	SignatureLength = 64
+)
+
+type Keypair struct {
+	pair    subkey.KeyPair
+	network uint8
+}
+
+func GenerateKeypair(network uint8) (*Keypair, error) {
+	seed := make([]byte, SeedLength)
+	_, err := rand.Read(seed)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeypairFromSeed(hexutil.Encode(seed), network)
+}
+
+// NewKeypairFromSeed derives a keypair from a secret URI, which is a hex seed or a mnemonic
+// phrase followed by an optional hard derivation path. Addresses use the given SS58 network prefix.
+func NewKeypairFromSeed(seed string, network uint8) (*Keypair, error) {
+	pair, err := subkey.DeriveKeyPair(ed25519.Scheme{}, seed)
+	if err != nil {
+		return nil, err
+	}
+	return &Keypair{pair, network}, nil
+}
+
+// Encode dumps the seed of the private key followed by the network prefix
+func (kp *Keypair) Encode() []byte {
+	return append(kp.pair.Seed(), kp.network)
+}
+
+// Decode initializes the keypair from the output of Encode
+func (kp *Keypair) Decode(in []byte) error {
+	if len(in) != SeedLength+1 {
+		return fmt.Errorf("invalid encoded keypair length %d", len(in))
+	}
+
+	pair, err := ed25519.Scheme{}.FromSeed(in[:SeedLength])
+	if err != nil {
+		return err
+	}
+
+	kp.pair = pair
+	kp.network = in[SeedLength]
+	return nil
+}
+
+// Address returns the ss58 formated address
+func (kp *Keypair) Address() string {
+	address, err := kp.pair.SS58Address(kp.network)
+	if err != nil {
+		return ""
+	}
+	return address
+}
+
+// PublicKey returns the publickey encoded as a string
+func (kp *Keypair) PublicKey() string {
+	return hexutil.Encode(kp.pair.Public())
+}
+
+func (kp *Keypair) Sign(msg []byte) ([]byte, error) {
+	return kp.pair.Sign(msg)
+}
+
+func (kp *Keypair) Verify(msg []byte, sig []byte) (bool, error) {
+	if len(sig) != SignatureLength {
+		return false, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	return kp.pair.Verify(msg, sig), nil
+}
+
+func (kp *Keypair) Type() crypto.KeyType {
+	return crypto.Ed25519Type
+}
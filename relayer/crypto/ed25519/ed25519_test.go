@@ -0,0 +1,84 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ed25519
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewKeypairFromSeed(t *testing.T) {
+	kp, err := NewKeypairFromSeed("//Alice", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Output of `subkey inspect --scheme ed25519 //Alice`
+	if kp.PublicKey() != "0x88dc3417d5058ec4b4503e0c12ea1a0a89be200fe98922423d4334014fa6b0ee" {
+		t.Fatalf("unexpected public key %s", kp.PublicKey())
+	}
+	if kp.Address() != "5FA9nQDVg267DEd8m1ZypXLBnvN7SFxYwV7ndqSYGiN9TTpu" {
+		t.Fatalf("unexpected address %s", kp.Address())
+	}
+}
+
+func TestAddressUsesNetworkPrefix(t *testing.T) {
+	kp, err := NewKeypairFromSeed("//Alice", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Polkadot addresses start with 1
+	if !strings.HasPrefix(kp.Address(), "1") {
+		t.Fatalf("unexpected address %s", kp.Address())
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	kp, err := GenerateKeypair(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("message")
+	sig, err := kp.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := kp.Verify(msg, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("signature did not verify")
+	}
+
+	ok, err = kp.Verify([]byte("other message"), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("signature verified for a different message")
+	}
+}
+
+func TestEncodeAndDecodeKeypair(t *testing.T) {
+	kp, err := NewKeypairFromSeed("//Alice", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc := kp.Encode()
+	res := new(Keypair)
+	err = res.Decode(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res.Encode(), kp.Encode()) || res.Address() != kp.Address() {
+		t.Fatalf("Fail: got %#v expected %#v", res, kp)
+	}
+}
@@ -3,18 +3,19 @@
 
 /*
 Package crypto is used to provide functionality to several keypair types.
-The current supported types are secp256k1 and sr25519.
+The current supported types are secp256k1, sr25519 and ed25519.
 
 Keypairs
 
 The keypair interface is used to bridge the different types of crypto formats.
 Every Keypair has both a Encode and Decode function that allows writing and reading from keystore files.
 There is also the Address and PublicKey functions that allow access to public facing fields.
+Sign and Verify allow messages to be signed without knowing the type of the keypair.
 
 Types
 
 A general overview on the secp256k1 can be found here: https://en.bitcoin.it/wiki/Secp256k1
-A general overview on the sr25519 type can be found here: https://wiki.polkadot.network/docs/en/learn-cryptography
+A general overview on the sr25519 and ed25519 types can be found here: https://wiki.polkadot.network/docs/en/learn-cryptography
 */
 package crypto
 
@@ -22,6 +23,7 @@ type KeyType = string
 
 const Sr25519Type KeyType = "sr25519"
 const Secp256k1Type KeyType = "secp256k1"
+const Ed25519Type KeyType = "ed25519"
 
 type Keypair interface {
 	// Encode is used to write the key to a file
@@ -32,4 +34,10 @@ type Keypair interface {
 	Address() string
 	// PublicKey returns the keypair's public key an encoded a string
 	PublicKey() string
+	// Sign signs the message with the private key
+	Sign(msg []byte) ([]byte, error)
+	// Verify checks that the signature of the message was made with the private key
+	Verify(msg []byte, sig []byte) (bool, error)
+	// Type returns the type of the keypair
+	Type() KeyType
 }
@@ -0,0 +1,48 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package keystore reads the files holding relayer keys and the passphrases of encrypted keys.
+package keystore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// PassphraseEnv is the environment variable from which the keystore passphrase is read
+// if no passphrase file is given
+const PassphraseEnv = "SNOWBRIDGE_KEYSTORE_PASSWORD"
+
+// ReadFile reads a file holding a key or passphrase, refusing files which other users can read
+func ReadFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Mode().Perm()&0o004 != 0 {
+		return nil, fmt.Errorf("%s is readable by all users, restrict its permissions with chmod 600", path)
+	}
+
+	return ioutil.ReadFile(path)
+}
+
+// Passphrase reads the keystore passphrase from the given file, or from the PassphraseEnv
+// environment variable if no file is given
+func Passphrase(passphraseFile string) (string, error) {
+	if passphraseFile != "" {
+		content, err := ReadFile(passphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to load keystore passphrase: %w", err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	passphrase, ok := os.LookupEnv(PassphraseEnv)
+	if !ok {
+		return "", fmt.Errorf("keystore passphrase not supplied, use a passphrase file or set %s", PassphraseEnv)
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package keystore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFileRefusesWorldReadableFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "key")
+	require.NoError(t, ioutil.WriteFile(path, []byte("secret"), 0o644))
+	_, err = ReadFile(path)
+	assert.Error(t, err)
+
+	require.NoError(t, os.Chmod(path, 0o600))
+	content, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(content))
+}
+
+func TestPassphrase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "passphrase")
+	require.NoError(t, ioutil.WriteFile(path, []byte("from file\n"), 0o600))
+	passphrase, err := Passphrase(path)
+	require.NoError(t, err)
+	assert.Equal(t, "from file", passphrase)
+
+	os.Setenv(PassphraseEnv, "from env")
+	defer os.Unsetenv(PassphraseEnv)
+	passphrase, err = Passphrase("")
+	require.NoError(t, err)
+	assert.Equal(t, "from env", passphrase)
+}
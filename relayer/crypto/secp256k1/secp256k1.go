@@ -4,16 +4,22 @@
 package secp256k1
 
 import (
+	"context"
 	"crypto/ecdsa"
+	"fmt"
+	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/snowfork/snowbridge/relayer/crypto"
 
 	secp256k1 "github.com/ethereum/go-ethereum/crypto"
 )
 
 var _ crypto.Keypair = &Keypair{}
+var _ crypto.Signer = &Keypair{}
 
 const PrivateKeyLength = 32
 
@@ -48,6 +54,16 @@ func NewKeypairFromString(priv string) (*Keypair, error) {
 	}, nil
 }
 
+// NewKeypairFromKeystore decrypts a key in the encrypted JSON keystore format used by go-ethereum
+func NewKeypairFromKeystore(keyJSON []byte, passphrase string) (*Keypair, error) {
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeypair(*key.PrivateKey), nil
+}
+
 func NewKeypair(pk ecdsa.PrivateKey) *Keypair {
 	pub := pk.Public()
 
@@ -103,3 +119,29 @@ func (kp *Keypair) PublicKey() string {
 func (kp *Keypair) PrivateKey() *ecdsa.PrivateKey {
 	return kp.private
 }
+
+// Sign signs the keccak256 hash of the message, returning the signature in the [R || S || V] format
+func (kp *Keypair) Sign(msg []byte) ([]byte, error) {
+	return secp256k1.Sign(secp256k1.Keccak256(msg), kp.private)
+}
+
+// Verify checks a signature of the keccak256 hash of the message in the [R || S] or [R || S || V] format
+func (kp *Keypair) Verify(msg []byte, sig []byte) (bool, error) {
+	if len(sig) == secp256k1.SignatureLength {
+		sig = sig[:secp256k1.SignatureLength-1]
+	}
+	if len(sig) != secp256k1.SignatureLength-1 {
+		return false, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+
+	return secp256k1.VerifySignature(secp256k1.FromECDSAPub(kp.public), secp256k1.Keccak256(msg), sig), nil
+}
+
+func (kp *Keypair) Type() crypto.KeyType {
+	return crypto.Secp256k1Type
+}
+
+// SignTx signs the transaction with the private key held in memory
+func (kp *Keypair) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewLondonSigner(chainID), kp.private)
+}
@@ -6,6 +6,11 @@ package secp256k1
 import (
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/google/uuid"
+
+	secp256k1 "github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestNewKeypairFromSeed(t *testing.T) {
@@ -36,3 +41,62 @@ func TestEncodeAndDecodeKeypair(t *testing.T) {
 		t.Fatalf("Fail: got %#v expected %#v", res, kp)
 	}
 }
+
+func TestNewKeypairFromKeystore(t *testing.T) {
+	kp, err := GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    secp256k1.PubkeyToAddress(*kp.public),
+		PrivateKey: kp.private,
+	}
+	keyJSON, err := keystore.EncryptKey(key, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := NewKeypairFromKeystore(keyJSON, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res, kp) {
+		t.Fatalf("Fail: got %#v expected %#v", res, kp)
+	}
+
+	_, err = NewKeypairFromKeystore(keyJSON, "wrong")
+	if err == nil {
+		t.Fatal("expected decryption with the wrong passphrase to fail")
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	kp, err := GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("message")
+	sig, err := kp.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := kp.Verify(msg, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("signature did not verify")
+	}
+
+	ok, err = kp.Verify([]byte("other message"), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("signature verified for a different message")
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package crypto
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Signer signs Ethereum transactions on behalf of a single account. The key may be held in memory
+// or by a separate signing service.
+type Signer interface {
+	// CommonAddress returns the address of the account
+	CommonAddress() common.Address
+	// SignTx signs the transaction for the chain with the given ID
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
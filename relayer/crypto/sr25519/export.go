@@ -0,0 +1,222 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sr25519
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/snowfork/go-substrate-rpc-client/v3/signature"
+	"github.com/vedhavyas/go-subkey"
+	subkeysr25519 "github.com/vedhavyas/go-subkey/sr25519"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Layout of the PKCS#8 encoded key in a Polkadot-JS export
+var (
+	pkcs8Header  = []byte{48, 83, 2, 1, 1, 48, 5, 6, 3, 43, 101, 112, 4, 34, 4, 32}
+	pkcs8Divider = []byte{161, 35, 3, 33, 0}
+)
+
+const (
+	miniSecretKeyLength = 32
+	secretKeyLength     = 64
+	publicKeyLength     = 32
+	scryptSaltLength    = 32
+	scryptParamsLength  = scryptSaltLength + 12
+	nonceLength         = 24
+)
+
+// Parameters used by Polkadot-JS to derive the encryption key from the passphrase
+const (
+	scryptN = 1 << 15
+	scryptP = 1
+	scryptR = 8
+)
+
+// exportedKey is the JSON format in which Polkadot-JS exports accounts
+type exportedKey struct {
+	Encoded  string `json:"encoded"`
+	Encoding struct {
+		Content []string `json:"content"`
+		Type    []string `json:"type"`
+		Version string   `json:"version"`
+	} `json:"encoding"`
+	Address string                 `json:"address"`
+	Meta    map[string]interface{} `json:"meta,omitempty"`
+}
+
+// NewKeypairFromExport decrypts an account exported as encrypted JSON by Polkadot-JS
+func NewKeypairFromExport(exportJSON []byte, passphrase string, network uint8) (*Keypair, error) {
+	var export exportedKey
+	err := json.Unmarshal(exportJSON, &export)
+	if err != nil {
+		return nil, fmt.Errorf("decode exported key: %w", err)
+	}
+
+	if len(export.Encoding.Content) != 2 || export.Encoding.Content[0] != "pkcs8" || export.Encoding.Content[1] != "sr25519" {
+		return nil, fmt.Errorf("unsupported key content %v, expected an sr25519 key", export.Encoding.Content)
+	}
+	if len(export.Encoding.Type) != 2 || export.Encoding.Type[0] != "scrypt" || export.Encoding.Type[1] != "xsalsa20-poly1305" {
+		return nil, fmt.Errorf("unsupported key encryption %v, expected scrypt and xsalsa20-poly1305", export.Encoding.Type)
+	}
+
+	encoded, err := base64.StdEncoding.DecodeString(export.Encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode exported key: %w", err)
+	}
+	if len(encoded) < scryptParamsLength+nonceLength {
+		return nil, fmt.Errorf("exported key is too short")
+	}
+
+	salt := encoded[:scryptSaltLength]
+	n := binary.LittleEndian.Uint32(encoded[32:36])
+	p := binary.LittleEndian.Uint32(encoded[36:40])
+	r := binary.LittleEndian.Uint32(encoded[40:44])
+
+	key, err := scrypt.Key([]byte(passphrase), salt, int(n), int(r), int(p), 32)
+	if err != nil {
+		return nil, err
+	}
+
+	var secretboxKey [32]byte
+	var nonce [nonceLength]byte
+	copy(secretboxKey[:], key)
+	copy(nonce[:], encoded[scryptParamsLength:scryptParamsLength+nonceLength])
+
+	decrypted, ok := secretbox.Open(nil, encoded[scryptParamsLength+nonceLength:], &nonce, &secretboxKey)
+	if !ok {
+		return nil, fmt.Errorf("could not decrypt exported key, the passphrase may be wrong")
+	}
+
+	secretKey, publicKey, err := decodePKCS8(decrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	// The secret key uses the ed25519 representation of the scalar, which is a multiple of the
+	// cofactor of the scalar expected by schnorrkel
+	divideScalarByCofactor(secretKey[:32])
+
+	pair, err := signature.KeyringPairFromSecret(hexutil.Encode(secretKey), network)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(pair.PublicKey, publicKey) {
+		return nil, fmt.Errorf("exported secret key does not match its public key")
+	}
+
+	return &Keypair{&pair}, nil
+}
+
+// Export encrypts the keypair in the JSON format used by Polkadot-JS, so that it can be read by
+// NewKeypairFromExport or imported into Polkadot-JS. Keys derived with soft junctions can't be exported.
+func (kp *Keypair) Export(passphrase string) ([]byte, error) {
+	pair, err := subkey.DeriveKeyPair(subkeysr25519.Scheme{}, kp.keyringPair.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	var secretKey []byte
+	switch seed := pair.Seed(); len(seed) {
+	case miniSecretKeyLength:
+		// The ed25519 form of the secret key expanded from a mini secret key
+		digest := sha512.Sum512(seed)
+		digest[0] &= 248
+		digest[31] &= 63
+		digest[31] |= 64
+		secretKey = digest[:]
+	case secretKeyLength:
+		secretKey = append([]byte{}, seed...)
+		multiplyScalarByCofactor(secretKey[:32])
+	default:
+		return nil, fmt.Errorf("keys derived with soft junctions can't be exported")
+	}
+
+	var plaintext []byte
+	plaintext = append(plaintext, pkcs8Header...)
+	plaintext = append(plaintext, secretKey...)
+	plaintext = append(plaintext, pkcs8Divider...)
+	plaintext = append(plaintext, kp.keyringPair.PublicKey...)
+
+	encoded := make([]byte, scryptParamsLength, scryptParamsLength+nonceLength+len(plaintext)+secretbox.Overhead)
+	_, err = rand.Read(encoded[:scryptSaltLength])
+	if err != nil {
+		return nil, err
+	}
+	binary.LittleEndian.PutUint32(encoded[32:36], scryptN)
+	binary.LittleEndian.PutUint32(encoded[36:40], scryptP)
+	binary.LittleEndian.PutUint32(encoded[40:44], scryptR)
+
+	key, err := scrypt.Key([]byte(passphrase), encoded[:scryptSaltLength], scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	var secretboxKey [32]byte
+	copy(secretboxKey[:], key)
+
+	var nonce [nonceLength]byte
+	_, err = rand.Read(nonce[:])
+	if err != nil {
+		return nil, err
+	}
+	encoded = append(encoded, nonce[:]...)
+	encoded = secretbox.Seal(encoded, plaintext, &nonce, &secretboxKey)
+
+	var export exportedKey
+	export.Encoded = base64.StdEncoding.EncodeToString(encoded)
+	export.Encoding.Content = []string{"pkcs8", "sr25519"}
+	export.Encoding.Type = []string{"scrypt", "xsalsa20-poly1305"}
+	export.Encoding.Version = "3"
+	export.Address = kp.keyringPair.Address
+	export.Meta = map[string]interface{}{}
+
+	return json.Marshal(export)
+}
+
+func decodePKCS8(decoded []byte) ([]byte, []byte, error) {
+	if len(decoded) != len(pkcs8Header)+secretKeyLength+len(pkcs8Divider)+publicKeyLength {
+		return nil, nil, fmt.Errorf("exported key has unexpected length %d", len(decoded))
+	}
+	if !bytes.HasPrefix(decoded, pkcs8Header) {
+		return nil, nil, fmt.Errorf("exported key has an invalid PKCS#8 header")
+	}
+
+	secretKey := decoded[len(pkcs8Header) : len(pkcs8Header)+secretKeyLength]
+	rest := decoded[len(pkcs8Header)+secretKeyLength:]
+	if !bytes.HasPrefix(rest, pkcs8Divider) {
+		return nil, nil, fmt.Errorf("exported key has an invalid PKCS#8 divider")
+	}
+
+	return secretKey, rest[len(pkcs8Divider):], nil
+}
+
+// Multiplies the little endian scalar by 8 in place
+func multiplyScalarByCofactor(scalar []byte) {
+	var high byte
+	for i := 0; i < len(scalar); i++ {
+		r := scalar[i] & 0xe0
+		scalar[i] <<= 3
+		scalar[i] += high
+		high = r >> 5
+	}
+}
+
+// Divides the little endian scalar by 8 in place
+func divideScalarByCofactor(scalar []byte) {
+	var low byte
+	for i := len(scalar) - 1; i >= 0; i-- {
+		r := scalar[i] & 0x07
+		scalar[i] >>= 3
+		scalar[i] += low
+		low = r << 5
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sr25519
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/snowfork/go-substrate-rpc-client/v3/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Mini secret key of the //Alice development account
+const aliceMiniSecretKey = "0xe5be9a5092b81bca64be81d212e7f2f9eba183bb7a90954f7b76361f6edb5c0a"
+
+// The //Alice development account encrypted with the passphrase "secret" in the Polkadot-JS export
+// format, using the scrypt parameters of Polkadot-JS
+const aliceExport = `{"encoded":"BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcAgAAAAQAAAAgAAAAHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwdBvc+4Wk7/h6GuA+lfN3m1OUFNZdsk22NYqMYtSFIOLnOQXKchNe3iP+oNx8ed37uFFov9/Msw13e2vPGxauATh3uZ4Jw6Bs/i6G89pRCgfrYGeMM7Mwi5PeMcsYVWUI/ONdleuXPMVe+PVDX5RIHqFSQh0vgwCZV+d1NnLEkWIDIyXLeV","encoding":{"content":["pkcs8","sr25519"],"type":["scrypt","xsalsa20-poly1305"],"version":"3"},"address":"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY","meta":{"name":"Alice","whenCreated":1633046400000}}`
+
+// Builds the PKCS#8 encoded key which Polkadot-JS encrypts. Polkadot-JS stores the secret key expanded
+// from the mini secret key in its ed25519 form.
+func pkcs8Key(miniSecretKey []byte, publicKey []byte) []byte {
+	digest := sha512.Sum512(miniSecretKey)
+	digest[0] &= 248
+	digest[31] &= 63
+	digest[31] |= 64
+
+	var plaintext []byte
+	plaintext = append(plaintext, 48, 83, 2, 1, 1, 48, 5, 6, 3, 43, 101, 112, 4, 34, 4, 32)
+	plaintext = append(plaintext, digest[:]...)
+	plaintext = append(plaintext, 161, 35, 3, 33, 0)
+	plaintext = append(plaintext, publicKey...)
+	return plaintext
+}
+
+// Encrypts the key derived from the mini secret key in the same way as Polkadot-JS, reading the salt
+// and nonce from entropy
+func exportKey(t *testing.T, entropy io.Reader, miniSecretKey []byte, publicKey []byte, passphrase string, n int) []byte {
+	params := make([]byte, 44)
+	_, err := io.ReadFull(entropy, params[:32])
+	require.NoError(t, err)
+	binary.LittleEndian.PutUint32(params[32:36], uint32(n))
+	binary.LittleEndian.PutUint32(params[36:40], 1)
+	binary.LittleEndian.PutUint32(params[40:44], 8)
+
+	key, err := scrypt.Key([]byte(passphrase), params[:32], n, 8, 1, 32)
+	require.NoError(t, err)
+	var secretboxKey [32]byte
+	copy(secretboxKey[:], key)
+	var nonce [24]byte
+	_, err = io.ReadFull(entropy, nonce[:])
+	require.NoError(t, err)
+
+	encoded := append(params, nonce[:]...)
+	encoded = secretbox.Seal(encoded, pkcs8Key(miniSecretKey, publicKey), &nonce, &secretboxKey)
+
+	export := map[string]interface{}{
+		"encoded": base64.StdEncoding.EncodeToString(encoded),
+		"encoding": map[string]interface{}{
+			"content": []string{"pkcs8", "sr25519"},
+			"type":    []string{"scrypt", "xsalsa20-poly1305"},
+			"version": "3",
+		},
+	}
+	exportJSON, err := json.Marshal(export)
+	require.NoError(t, err)
+	return exportJSON
+}
+
+// Decrypts an export in the same way as Polkadot-JS, returning the PKCS#8 encoded key
+func decryptExport(t *testing.T, exportJSON []byte, passphrase string) []byte {
+	var export struct {
+		Encoded string `json:"encoded"`
+	}
+	require.NoError(t, json.Unmarshal(exportJSON, &export))
+	encoded, err := base64.StdEncoding.DecodeString(export.Encoded)
+	require.NoError(t, err)
+
+	n := binary.LittleEndian.Uint32(encoded[32:36])
+	p := binary.LittleEndian.Uint32(encoded[36:40])
+	r := binary.LittleEndian.Uint32(encoded[40:44])
+	// Polkadot-JS only accepts these parameters
+	require.Equal(t, []uint32{1 << 15, 1, 8}, []uint32{n, p, r})
+
+	key, err := scrypt.Key([]byte(passphrase), encoded[:32], int(n), int(r), int(p), 32)
+	require.NoError(t, err)
+	var secretboxKey [32]byte
+	copy(secretboxKey[:], key)
+	var nonce [24]byte
+	copy(nonce[:], encoded[44:68])
+
+	plaintext, ok := secretbox.Open(nil, encoded[68:], &nonce, &secretboxKey)
+	require.True(t, ok)
+	return plaintext
+}
+
+func TestNewKeypairFromExportFixture(t *testing.T) {
+	kp, err := NewKeypairFromExport([]byte(aliceExport), "secret", 42)
+	require.NoError(t, err)
+	assert.Equal(t, "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", kp.Address())
+
+	_, err = NewKeypairFromExport([]byte(aliceExport), "wrong", 42)
+	assert.Error(t, err)
+}
+
+func TestNewKeypairFromExport(t *testing.T) {
+	miniSecretKey := make([]byte, 32)
+	_, err := rand.Read(miniSecretKey)
+	require.NoError(t, err)
+
+	expected, err := NewKeypairFromSeed(hexutil.Encode(miniSecretKey), 42)
+	require.NoError(t, err)
+
+	exportJSON := exportKey(t, rand.Reader, miniSecretKey, expected.AsKeyringPair().PublicKey, "secret", 1<<10)
+
+	kp, err := NewKeypairFromExport(exportJSON, "secret", 42)
+	require.NoError(t, err)
+	assert.Equal(t, expected.PublicKey(), kp.PublicKey())
+	assert.Equal(t, expected.Address(), kp.Address())
+
+	// The decrypted key signs for the same account as the mini secret key
+	sig, err := signature.Sign([]byte("message"), kp.AsKeyringPair().URI)
+	require.NoError(t, err)
+	ok, err := signature.Verify([]byte("message"), sig, expected.AsKeyringPair().URI)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = NewKeypairFromExport(exportJSON, "wrong", 42)
+	assert.Error(t, err)
+}
+
+func TestExport(t *testing.T) {
+	miniSecretKey, err := hexutil.Decode(aliceMiniSecretKey)
+	require.NoError(t, err)
+
+	kp, err := NewKeypairFromSeed("//Alice", 42)
+	require.NoError(t, err)
+
+	exportJSON, err := kp.Export("secret")
+	require.NoError(t, err)
+	assert.Equal(t, pkcs8Key(miniSecretKey, kp.AsKeyringPair().PublicKey), decryptExport(t, exportJSON, "secret"))
+
+	// Keys imported from an export are exported with the same secret key
+	for _, uri := range []string{"//Alice", "0x" + strings.Repeat("ab", 32)} {
+		kp, err := NewKeypairFromSeed(uri, 42)
+		require.NoError(t, err)
+
+		exportJSON, err := kp.Export("secret")
+		require.NoError(t, err)
+
+		imported, err := NewKeypairFromExport(exportJSON, "secret", 42)
+		require.NoError(t, err)
+		assert.Equal(t, kp.PublicKey(), imported.PublicKey())
+
+		reexportJSON, err := imported.Export("secret")
+		require.NoError(t, err)
+		assert.Equal(t, decryptExport(t, exportJSON, "secret"), decryptExport(t, reexportJSON, "secret"))
+	}
+
+	kp, err = NewKeypairFromSeed("//Alice/soft", 42)
+	require.NoError(t, err)
+	_, err = kp.Export("secret")
+	assert.Error(t, err)
+}
@@ -62,3 +62,17 @@ func (kp *Keypair) Address() string {
 func (kp *Keypair) PublicKey() string {
 	return hexutil.Encode(kp.keyringPair.PublicKey)
 }
+
+// Sign signs the message, which is hashed first if it is longer than 256 bytes
+func (kp *Keypair) Sign(msg []byte) ([]byte, error) {
+	return signature.Sign(msg, kp.keyringPair.URI)
+}
+
+// Verify checks a signature made by Sign
+func (kp *Keypair) Verify(msg []byte, sig []byte) (bool, error) {
+	return signature.Verify(msg, sig, kp.keyringPair.URI)
+}
+
+func (kp *Keypair) Type() crypto.KeyType {
+	return crypto.Sr25519Type
+}
@@ -54,3 +54,32 @@ func TestEncodeAndDecodeKeypair(t *testing.T) {
 		t.Fatalf("Fail: got %#v expected %#v", res, kp)
 	}
 }
+
+func TestSignAndVerify(t *testing.T) {
+	kp, err := NewKeypairFromSeed("//Alice", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("message")
+	sig, err := kp.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := kp.Verify(msg, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("signature did not verify")
+	}
+
+	ok, err = kp.Verify([]byte("other message"), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("signature verified for a different message")
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package web3signer signs Ethereum transactions using a remote signing service which implements
+// the eth1 JSON-RPC API of Web3Signer, so that the relayer doesn't hold the private key.
+package web3signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/snowfork/snowbridge/relayer/crypto"
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+var _ crypto.Signer = &Signer{}
+
+type Signer struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// transactionArgs are the parameters of eth_signTransaction
+type transactionArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+}
+
+// NewSigner creates a signer for the account with the given address, which must be held by
+// the signing service at the given URL
+func NewSigner(url string, address common.Address) (*Signer, error) {
+	client, err := rpc.DialHTTP(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		client:  client,
+		address: address,
+	}, nil
+}
+
+// CheckAccount returns an error if the signing service doesn't hold the key of the account
+func (s *Signer) CheckAccount(ctx context.Context) error {
+	var accounts []common.Address
+	err := s.client.CallContext(ctx, &accounts, "eth_accounts")
+	if err != nil {
+		return fmt.Errorf("list accounts of remote signer: %w", err)
+	}
+
+	for _, account := range accounts {
+		if account == s.address {
+			return nil
+		}
+	}
+	return retry.Fatal(fmt.Errorf("remote signer does not hold the key for account %s", s.address.Hex()))
+}
+
+func (s *Signer) CommonAddress() common.Address {
+	return s.address
+}
+
+// SignTx asks the signing service to sign the transaction. The signed transaction is checked
+// against the one requested, as the service is trusted with the key but not with the contents.
+func (s *Signer) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := transactionArgs{
+		From:  s.address,
+		To:    tx.To(),
+		Gas:   hexutil.Uint64(tx.Gas()),
+		Value: (*hexutil.Big)(tx.Value()),
+		Data:  tx.Data(),
+		Nonce: hexutil.Uint64(tx.Nonce()),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var raw hexutil.Bytes
+	err := s.client.CallContext(ctx, &raw, "eth_signTransaction", args)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+
+	var signed types.Transaction
+	err = signed.UnmarshalBinary(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode transaction signed by remote signer: %w", err)
+	}
+
+	signer := types.NewLondonSigner(chainID)
+	if signer.Hash(&signed) != signer.Hash(tx) {
+		return nil, retry.Fatal(fmt.Errorf("remote signer signed a different transaction"))
+	}
+
+	sender, err := types.Sender(signer, &signed)
+	if err != nil {
+		return nil, fmt.Errorf("recover sender of transaction signed by remote signer: %w", err)
+	}
+	if sender != s.address {
+		return nil, retry.Fatal(fmt.Errorf("remote signer signed with account %s instead of %s", sender.Hex(), s.address.Hex()))
+	}
+
+	return &signed, nil
+}
+
+func (s *Signer) Close() {
+	s.client.Close()
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package web3signer
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snowfork/snowbridge/relayer/crypto/secp256k1"
+)
+
+var chainID = big.NewInt(15)
+
+// Stands in for Web3Signer, signing with a key held in memory
+type signerService struct {
+	keypair *secp256k1.Keypair
+	// Changes the transaction before signing it
+	tamper bool
+}
+
+func (s *signerService) Accounts() []common.Address {
+	return []common.Address{s.keypair.CommonAddress()}
+}
+
+func (s *signerService) SignTransaction(args transactionArgs) (hexutil.Bytes, error) {
+	value := args.Value.ToInt()
+	if s.tamper {
+		value = new(big.Int).Add(value, big.NewInt(1))
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     value,
+		Data:      args.Data,
+	})
+
+	signed, err := s.keypair.SignTx(context.Background(), tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+func newTestSigner(t *testing.T, service *signerService, address common.Address) *Signer {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	signer, err := NewSigner(httpServer.URL, address)
+	require.NoError(t, err)
+	t.Cleanup(signer.Close)
+	return signer
+}
+
+func newTestTransaction() *types.Transaction {
+	to := common.HexToAddress("0x992B9df075935E522EC7950F37eC8557e86f6fdb")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1000),
+		Data:      []byte{0xca, 0xfe},
+	})
+}
+
+func TestSignTx(t *testing.T) {
+	keypair := secp256k1.Alice()
+	signer := newTestSigner(t, &signerService{keypair: keypair}, keypair.CommonAddress())
+	ctx := context.Background()
+
+	require.NoError(t, signer.CheckAccount(ctx))
+
+	tx := newTestTransaction()
+	signed, err := signer.SignTx(ctx, tx, chainID)
+	require.NoError(t, err)
+
+	expected, err := keypair.SignTx(ctx, tx, chainID)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Hash(), signed.Hash())
+}
+
+func TestSignTxRejectsTamperedTransaction(t *testing.T) {
+	keypair := secp256k1.Alice()
+	signer := newTestSigner(t, &signerService{keypair: keypair, tamper: true}, keypair.CommonAddress())
+
+	_, err := signer.SignTx(context.Background(), newTestTransaction(), chainID)
+	assert.Error(t, err)
+}
+
+func TestSignTxRejectsOtherAccount(t *testing.T) {
+	signer := newTestSigner(t, &signerService{keypair: secp256k1.Bob()}, secp256k1.Alice().CommonAddress())
+	ctx := context.Background()
+
+	assert.Error(t, signer.CheckAccount(ctx))
+
+	_, err := signer.SignTx(ctx, newTestTransaction(), chainID)
+	assert.Error(t, err)
+}
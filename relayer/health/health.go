@@ -0,0 +1,250 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package health tracks whether a relay is ready to do work and whether it is still making progress,
+// and reports both over HTTP for orchestrators.
+//
+// A relay is ready once it has been started and every expected condition, such as a connection
+// being established or a catch-up phase completing, is satisfied. A ready relay is healthy as long
+// as some pipeline stage has reported progress within the stall timeout.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStallTimeout is the time without progress after which a relay is considered unhealthy
+const DefaultStallTimeout = 10 * time.Minute
+
+// Condition must be satisfied before the relay is ready
+type Condition struct {
+	monitor   *Monitor
+	name      string
+	satisfied bool
+}
+
+// Satisfy marks the condition as met
+func (c *Condition) Satisfy() {
+	c.monitor.mu.Lock()
+	defer c.monitor.mu.Unlock()
+	c.satisfied = true
+	c.monitor.updateReady()
+}
+
+// Unsatisfy marks the condition as no longer met, such as when a connection is lost
+func (c *Condition) Unsatisfy() {
+	c.monitor.mu.Lock()
+	defer c.monitor.mu.Unlock()
+	c.satisfied = false
+	c.monitor.updateReady()
+}
+
+// Satisfied returns whether the condition is met
+func (c *Condition) Satisfied() bool {
+	c.monitor.mu.Lock()
+	defer c.monitor.mu.Unlock()
+	return c.satisfied
+}
+
+// Remove drops the condition, so that readiness no longer depends on it
+func (c *Condition) Remove() {
+	c.monitor.mu.Lock()
+	defer c.monitor.mu.Unlock()
+	for i, condition := range c.monitor.conditions {
+		if condition == c {
+			c.monitor.conditions = append(c.monitor.conditions[:i], c.monitor.conditions[i+1:]...)
+			break
+		}
+	}
+	c.monitor.updateReady()
+}
+
+type Monitor struct {
+	mu           sync.Mutex
+	now          func() time.Time
+	stallTimeout time.Duration
+	started      bool
+	conditions   []*Condition
+	readySince   time.Time
+	progress     map[string]time.Time
+}
+
+func NewMonitor(stallTimeout time.Duration) *Monitor {
+	return &Monitor{
+		now:          time.Now,
+		stallTimeout: stallTimeout,
+		progress:     make(map[string]time.Time),
+	}
+}
+
+// SetStallTimeout sets the time without progress after which the relay is considered unhealthy
+func (m *Monitor) SetStallTimeout(stallTimeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stallTimeout = stallTimeout
+}
+
+// Expect adds a condition which must be satisfied before the relay is ready
+func (m *Monitor) Expect(name string) *Condition {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	condition := &Condition{monitor: m, name: name}
+	m.conditions = append(m.conditions, condition)
+	m.updateReady()
+	return condition
+}
+
+// ExpectUntil adds a condition which is removed once the context is done. Relays use it for the
+// conditions of a single run, so that a run which failed, such as while catching up, doesn't keep
+// the restarted relay from being ready.
+func (m *Monitor) ExpectUntil(ctx context.Context, name string) *Condition {
+	condition := m.Expect(name)
+	go func() {
+		<-ctx.Done()
+		condition.Remove()
+	}()
+	return condition
+}
+
+// Started records that the relay has started all of its components. Conditions are expected while
+// the relay starts, so it can't be ready before then.
+func (m *Monitor) Started() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started = true
+	m.updateReady()
+}
+
+// Progress records that the given pipeline stage has done some work
+func (m *Monitor) Progress(stage string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progress[stage] = m.now()
+}
+
+// Tracks the time at which the relay became ready. Called with the lock held.
+func (m *Monitor) updateReady() {
+	ready := m.started && len(m.unsatisfied()) == 0
+	if !ready {
+		m.readySince = time.Time{}
+	} else if m.readySince.IsZero() {
+		m.readySince = m.now()
+	}
+}
+
+func (m *Monitor) unsatisfied() []string {
+	var names []string
+	for _, condition := range m.conditions {
+		if !condition.satisfied {
+			names = append(names, condition.name)
+		}
+	}
+	return names
+}
+
+// Ready returns an error listing the unsatisfied conditions if the relay isn't ready
+func (m *Monitor) Ready() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.started {
+		return fmt.Errorf("relay is starting")
+	}
+	unsatisfied := m.unsatisfied()
+	if len(unsatisfied) > 0 {
+		return fmt.Errorf("waiting for: %s", strings.Join(unsatisfied, ", "))
+	}
+	return nil
+}
+
+// Healthy returns an error if no pipeline stage has made progress within the stall timeout.
+// A relay which isn't ready yet is healthy, so that long catch-up phases don't fail it.
+func (m *Monitor) Healthy() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.readySince.IsZero() {
+		return nil
+	}
+
+	lastProgress := m.readySince
+	for _, at := range m.progress {
+		if at.After(lastProgress) {
+			lastProgress = at
+		}
+	}
+
+	stalledFor := m.now().Sub(lastProgress)
+	if stalledFor <= m.stallTimeout {
+		return nil
+	}
+	if len(m.progress) == 0 {
+		return fmt.Errorf("no progress for %s", stalledFor.Round(time.Second))
+	}
+
+	stages := make([]string, 0, len(m.progress))
+	for stage := range m.progress {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	return fmt.Errorf("no progress for %s (stages: %s)", stalledFor.Round(time.Second), strings.Join(stages, ", "))
+}
+
+// Handler serves the /healthz and /readyz endpoints
+func (m *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", checkHandler(m.Healthy))
+	mux.HandleFunc("/readyz", checkHandler(m.Ready))
+	return mux
+}
+
+func checkHandler(check func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		err := check()
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	}
+}
+
+var defaultMonitor = NewMonitor(DefaultStallTimeout)
+
+// SetStallTimeout sets the stall timeout of the default monitor
+func SetStallTimeout(stallTimeout time.Duration) {
+	defaultMonitor.SetStallTimeout(stallTimeout)
+}
+
+// Expect adds a condition to the default monitor
+func Expect(name string) *Condition {
+	return defaultMonitor.Expect(name)
+}
+
+// ExpectUntil adds a condition to the default monitor which is removed once the context is done
+func ExpectUntil(ctx context.Context, name string) *Condition {
+	return defaultMonitor.ExpectUntil(ctx, name)
+}
+
+// Started records that the relay using the default monitor has started
+func Started() {
+	defaultMonitor.Started()
+}
+
+// Progress records progress of a pipeline stage in the default monitor
+func Progress(stage string) {
+	defaultMonitor.Progress(stage)
+}
+
+// Handler serves the endpoints of the default monitor
+func Handler() http.Handler {
+	return defaultMonitor.Handler()
+}
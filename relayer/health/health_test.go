@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMonitor(stallTimeout time.Duration) (*Monitor, *time.Time) {
+	now := time.Unix(1000, 0)
+	monitor := NewMonitor(stallTimeout)
+	monitor.now = func() time.Time { return now }
+	return monitor, &now
+}
+
+func TestReady(t *testing.T) {
+	monitor, _ := newTestMonitor(time.Minute)
+
+	connected := monitor.Expect("connection")
+	synced := monitor.Expect("sync")
+	assert.EqualError(t, monitor.Ready(), "relay is starting")
+
+	monitor.Started()
+	assert.EqualError(t, monitor.Ready(), "waiting for: connection, sync")
+
+	connected.Satisfy()
+	assert.EqualError(t, monitor.Ready(), "waiting for: sync")
+
+	synced.Satisfy()
+	assert.NoError(t, monitor.Ready())
+}
+
+func TestReadyAfterConnectionLost(t *testing.T) {
+	monitor, now := newTestMonitor(time.Minute)
+
+	connected := monitor.Expect("connection")
+	monitor.Started()
+	connected.Satisfy()
+	assert.NoError(t, monitor.Ready())
+
+	connected.Unsatisfy()
+	assert.EqualError(t, monitor.Ready(), "waiting for: connection")
+	// A relay which is waiting to reconnect isn't considered stalled
+	*now = now.Add(time.Hour)
+	assert.NoError(t, monitor.Healthy())
+
+	connected.Satisfy()
+	assert.NoError(t, monitor.Ready())
+}
+
+func TestReadyAfterRestart(t *testing.T) {
+	monitor, _ := newTestMonitor(time.Minute)
+	monitor.Started()
+
+	// The first run fails while catching up
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor.ExpectUntil(ctx, "catch-up")
+	assert.EqualError(t, monitor.Ready(), "waiting for: catch-up")
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		return monitor.Ready() == nil
+	}, time.Second, time.Millisecond)
+
+	// The restarted run catches up
+	caughtUp := monitor.ExpectUntil(context.Background(), "catch-up")
+	assert.EqualError(t, monitor.Ready(), "waiting for: catch-up")
+	caughtUp.Satisfy()
+	assert.NoError(t, monitor.Ready())
+}
+
+func TestHealthy(t *testing.T) {
+	monitor, now := newTestMonitor(time.Minute)
+
+	synced := monitor.Expect("sync")
+	monitor.Started()
+
+	// Not ready yet, so a long catch-up doesn't fail the health check
+	*now = now.Add(time.Hour)
+	assert.NoError(t, monitor.Healthy())
+
+	synced.Satisfy()
+	*now = now.Add(59 * time.Second)
+	assert.NoError(t, monitor.Healthy())
+
+	*now = now.Add(2 * time.Second)
+	assert.EqualError(t, monitor.Healthy(), "no progress for 1m1s")
+
+	monitor.Progress("writer")
+	assert.NoError(t, monitor.Healthy())
+
+	*now = now.Add(2 * time.Minute)
+	monitor.Progress("listener")
+	*now = now.Add(2 * time.Minute)
+	assert.EqualError(t, monitor.Healthy(), "no progress for 2m0s (stages: listener, writer)")
+}
+
+func TestHandler(t *testing.T) {
+	monitor, _ := newTestMonitor(time.Minute)
+	monitor.Expect("connection")
+	handler := monitor.Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "relay is starting\n", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ok\n", recorder.Body.String())
+}
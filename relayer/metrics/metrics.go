@@ -0,0 +1,90 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package metrics exposes the Prometheus metrics collected by the relays and their health checks
+// over HTTP, and holds the metrics shared by all relays.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/retry"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+var (
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "snowbridge",
+		Subsystem: "rpc",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of RPC requests to chain nodes, by endpoint and method.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"endpoint", "method"})
+
+	rpcErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "rpc",
+		Name:      "errors_total",
+		Help:      "RPC requests to chain nodes which failed, by endpoint and method.",
+	}, []string{"endpoint", "method"})
+)
+
+// ObserveRPC records the latency and outcome of an RPC request started at the given time. The endpoint
+// is labelled by its scheme and host only.
+func ObserveRPC(endpoint string, method string, start time.Time, err error) {
+	endpoint = retry.EndpointLabel(endpoint)
+	rpcDuration.WithLabelValues(endpoint, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		rpcErrors.WithLabelValues(endpoint, method).Inc()
+	}
+}
+
+// Serve exposes the metrics at /metrics, and the health checks at /healthz and /readyz, on the
+// given address until the context is cancelled
+func Serve(ctx context.Context, eg *errgroup.Group, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	healthHandler := health.Handler()
+	mux.Handle("/healthz", healthHandler)
+	mux.Handle("/readyz", healthHandler)
+
+	// Listen before returning so that a bad address fails relay startup
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: mux}
+
+	eg.Go(func() error {
+		log.WithField("address", listener.Addr().String()).Info("Serving metrics")
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("Metrics server failed")
+			return err
+		}
+		return nil
+	})
+
+	eg.Go(func() error {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	})
+
+	return nil
+}
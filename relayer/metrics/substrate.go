@@ -0,0 +1,73 @@
+// Copyright 2020 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	gsrpc "github.com/snowfork/go-substrate-rpc-client/v3"
+	"github.com/snowfork/go-substrate-rpc-client/v3/client"
+	gethrpc "github.com/snowfork/go-substrate-rpc-client/v3/gethrpc"
+	"github.com/snowfork/go-substrate-rpc-client/v3/rpc"
+
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+// instrumentedClient records the latency and errors of the requests made through a substrate client
+type instrumentedClient struct {
+	client.Client
+}
+
+func (c *instrumentedClient) Call(result interface{}, method string, args ...interface{}) error {
+	start := time.Now()
+	err := c.Client.Call(result, method, args...)
+	ObserveRPC(c.URL(), method, start, err)
+	return err
+}
+
+func (c *instrumentedClient) Subscribe(ctx context.Context, namespace, subscribeMethodSuffix, unsubscribeMethodSuffix,
+	notificationMethodSuffix string, channel interface{}, args ...interface{}) (*gethrpc.ClientSubscription, error) {
+	start := time.Now()
+	sub, err := c.Client.Subscribe(ctx, namespace, subscribeMethodSuffix, unsubscribeMethodSuffix,
+		notificationMethodSuffix, channel, args...)
+	ObserveRPC(c.URL(), namespace+subscribeMethodSuffix, start, err)
+	return sub, err
+}
+
+// Close closes the wrapped client
+func (c *instrumentedClient) Close() {
+	if closer, ok := c.Client.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// NewSubstrateAPI connects to the substrate node at the given URL. Unlike gsrpc.NewSubstrateAPI,
+// requests made through the returned API are recorded in the RPC metrics, and retried when they
+// fail with transient errors.
+func NewSubstrateAPI(url string) (*gsrpc.SubstrateAPI, error) {
+	cl, err := client.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	// Each attempt is recorded separately
+	wrapped := retry.NewSubstrateClient(&instrumentedClient{Client: cl}, retry.DefaultPolicy)
+
+	newRPC, err := rpc.NewRPC(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gsrpc.SubstrateAPI{
+		RPC:    newRPC,
+		Client: wrapped,
+	}, nil
+}
+
+// CloseSubstrateAPI closes the connection of an API created by NewSubstrateAPI
+func CloseSubstrateAPI(api *gsrpc.SubstrateAPI) {
+	if closer, ok := api.Client.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
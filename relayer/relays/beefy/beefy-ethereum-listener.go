@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 
+	geth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jinzhu/gorm"
@@ -17,11 +18,15 @@ import (
 	"github.com/snowfork/snowbridge/relayer/chain"
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
 	"github.com/snowfork/snowbridge/relayer/contracts/beefylightclient"
+	"github.com/snowfork/snowbridge/relayer/health"
 	"github.com/snowfork/snowbridge/relayer/relays/beefy/store"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// Number of blocks queried at a time for light client events
+const eventBlockWindow = 1000
+
 // Listener streams the Ethereum blockchain for application events
 type BeefyEthereumListener struct {
 	config           *SinkConfig
@@ -69,6 +74,7 @@ func (li *BeefyEthereumListener) Start(ctx context.Context, eg *errgroup.Group)
 	if err != nil {
 		return 0, err
 	}
+	lightClientBeefyBlock.Set(float64(latestBeefyBlock))
 
 	// Fetch BLOCK_WAIT_PERIOD from light client bridge contract
 	blockWaitPeriod, err := li.beefyLightClient.ContractCaller.BLOCKWAITPERIOD(nil)
@@ -104,6 +110,20 @@ func (li *BeefyEthereumListener) pollEventsAndHeaders(ctx context.Context, desce
 	}
 	defer sub.Unsubscribe()
 
+	// The last block for which light client events have been processed. Events are only processed once
+	// their block has at least descendantsUntilFinal descendants, so that we never act on events which
+	// may still be reorged out. It is kept in the database, so that events emitted while the relay was
+	// stopped are still processed when it starts again.
+	var lastProcessedBlock *uint64
+	blockNumber, ok, err := li.beefyDB.GetLastProcessedBlock()
+	if err != nil {
+		return err
+	}
+	if ok {
+		lastProcessedBlock = &blockNumber
+		log.WithField("blockNumber", blockNumber).Info("Resuming ethereum listener after last processed block")
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -121,29 +141,78 @@ func (li *BeefyEthereumListener) pollEventsAndHeaders(ctx context.Context, desce
 				"blockNumber": blockNumber,
 			}).Debug("Processing new ethereum header")
 
+			li.observeState(ctx)
+			health.Progress("ethereum-listener")
+
 			err := li.forwardWitnessedBeefyJustifications(ctx)
 			if err != nil {
 				return err
 			}
 
-			err = li.processInitialVerificationSuccessfulEvents(ctx, blockNumber)
+			err = li.recheckSentItems(ctx)
 			if err != nil {
 				return err
 			}
 
-			err = li.forwardReadyToCompleteItems(ctx, blockNumber, descendantsUntilFinal)
+			if blockNumber < descendantsUntilFinal {
+				continue
+			}
+			finalizedBlockNumber := blockNumber - descendantsUntilFinal
+
+			startBlock := finalizedBlockNumber
+			if lastProcessedBlock != nil {
+				if finalizedBlockNumber <= *lastProcessedBlock {
+					continue
+				}
+				startBlock = *lastProcessedBlock + 1
+			}
+
+			err = li.processFinalizedBlocks(ctx, startBlock, finalizedBlockNumber)
 			if err != nil {
 				return err
 			}
 
-			err = li.processFinalVerificationSuccessfulEvents(ctx, blockNumber, blockNumber)
+			err = li.forwardReadyToCompleteItems(ctx, blockNumber, descendantsUntilFinal)
 			if err != nil {
 				return err
 			}
+
+			lastProcessedBlock = &finalizedBlockNumber
 		}
 	}
 }
 
+// processFinalizedBlocks processes light client events emitted in blocks startBlock to endBlock. After
+// the relay was stopped the range can be long, so it is queried in windows of eventBlockWindow blocks
+// to stay within the range limits of eth_getLogs. Progress is persisted after each window.
+func (li *BeefyEthereumListener) processFinalizedBlocks(ctx context.Context, startBlock, endBlock uint64) error {
+	for start := startBlock; start <= endBlock; {
+		end := endBlock
+		if end-start >= eventBlockWindow {
+			end = start + eventBlockWindow - 1
+		}
+
+		err := li.processInitialVerificationSuccessfulEvents(ctx, start, end)
+		if err != nil {
+			return err
+		}
+
+		err = li.processFinalVerificationSuccessfulEvents(ctx, start, end)
+		if err != nil {
+			return err
+		}
+
+		err = li.beefyDB.PutLastProcessedBlock(end)
+		if err != nil {
+			return err
+		}
+
+		start = end + 1
+	}
+
+	return nil
+}
+
 // queryInitialVerificationSuccessfulEvents queries ContractInitialVerificationSuccessful events from the BeefyLightClient contract
 func (li *BeefyEthereumListener) queryInitialVerificationSuccessfulEvents(ctx context.Context, start uint64,
 	end *uint64) ([]*beefylightclient.ContractInitialVerificationSuccessful, error) {
@@ -175,17 +244,19 @@ func (li *BeefyEthereumListener) queryInitialVerificationSuccessfulEvents(ctx co
 // InitialVerificationTxSent to InitialVerificationTxConfirmed
 func (li *BeefyEthereumListener) processInitialVerificationSuccessfulEvents(
 	ctx context.Context,
-	blockNumber uint64,
+	startBlock uint64,
+	endBlock uint64,
 ) error {
-	events, err := li.queryInitialVerificationSuccessfulEvents(ctx, blockNumber, &blockNumber)
+	events, err := li.queryInitialVerificationSuccessfulEvents(ctx, startBlock, &endBlock)
 	if err != nil {
 		log.WithError(err).Error("Failure querying InitialVerificationSuccessful events")
 		return err
 	}
 
 	log.WithFields(log.Fields{
-		"block": blockNumber,
-		"count": len(events),
+		"startBlock": startBlock,
+		"endBlock":   endBlock,
+		"count":      len(events),
 	}).Debug("Queried for InitialVerificationSuccessful events")
 
 	for _, event := range events {
@@ -197,13 +268,15 @@ func (li *BeefyEthereumListener) processInitialVerificationSuccessfulEvents(
 		}).Info("Processing InitialVerificationSuccessful event")
 
 		// Only process events emitted by transactions sent from our node
-		if event.Prover != li.ethereumConn.GetKP().CommonAddress() {
+		if event.Prover != li.ethereumConn.Signer().CommonAddress() {
 			log.WithFields(logrus.Fields{
 				"Prover": event.Prover.Hex(),
 			}).Info("Skipping InitialVerificationSuccessful event as it has an unknown prover address")
 			continue
 		}
 
+		li.recordGasUsed(ctx, newSignatureCommitmentMethod, event.Raw.TxHash)
+
 		item, err := li.beefyDB.GetItemByInitialVerificationTxHash(event.Raw.TxHash)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -287,7 +360,7 @@ func (li *BeefyEthereumListener) processFinalVerificationSuccessfulEvents(
 			"Prover":      event.Prover.Hex(),
 		}).Info("Processing FinalVerificationSuccessful event")
 
-		if event.Prover != li.ethereumConn.GetKP().CommonAddress() {
+		if event.Prover != li.ethereumConn.Signer().CommonAddress() {
 			log.WithFields(logrus.Fields{
 				"ID":     event.Id.Int64(),
 				"Prover": event.Prover.Hex(),
@@ -295,6 +368,8 @@ func (li *BeefyEthereumListener) processFinalVerificationSuccessfulEvents(
 			continue
 		}
 
+		li.recordGasUsed(ctx, completeSignatureCommitmentMethod, event.Raw.TxHash)
+
 		item, err := li.beefyDB.GetItemByID(event.Id.Int64())
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -349,9 +424,14 @@ func (li *BeefyEthereumListener) forwardReadyToCompleteItems(ctx context.Context
 	for _, item := range initialVerificationItems {
 		if item.CompleteOnBlock+descendantsUntilFinal <= blockNumber {
 			// Fetch intended completion block's hash
-			block, err := li.ethereumConn.GetClient().BlockByNumber(ctx, big.NewInt(int64(item.CompleteOnBlock)))
+			header, err := li.ethereumConn.GetClient().HeaderByNumber(ctx, new(big.Int).SetUint64(item.CompleteOnBlock))
 			if err != nil {
-				log.WithError(err).Error("Failure fetching inclusion block")
+				// The item will be retried upon the next header
+				log.WithError(err).WithFields(logrus.Fields{
+					"ID":              item.ID,
+					"completeOnBlock": item.CompleteOnBlock,
+				}).Error("Failure fetching completion block for random seed")
+				continue
 			}
 
 			log.Infof(
@@ -359,7 +439,7 @@ func (li *BeefyEthereumListener) forwardReadyToCompleteItems(ctx context.Context
 				item.ID,
 			)
 			item.Status = store.ReadyToComplete
-			item.RandomSeed = block.Hash()
+			item.RandomSeed = header.Hash()
 
 			select {
 			case <-ctx.Done():
@@ -371,3 +451,118 @@ func (li *BeefyEthereumListener) forwardReadyToCompleteItems(ctx context.Context
 
 	return nil
 }
+
+// recheckSentItems checks that the transactions of items awaiting confirmation are still known to the
+// Ethereum node. Items whose transaction has disappeared, for example because the block including it was
+// reorged out and the transaction was dropped, are reset so that the transaction is sent again.
+func (li *BeefyEthereumListener) recheckSentItems(ctx context.Context) error {
+	initialItems, err := li.beefyDB.GetItemsByStatus(store.InitialVerificationTxSent)
+	if err != nil {
+		log.WithError(err).Error("Failure querying beefy DB for items by InitialVerificationTxSent status")
+		return err
+	}
+
+	for _, item := range initialItems {
+		exists, err := li.transactionExists(ctx, item.InitialVerificationTxHash)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		log.WithFields(logrus.Fields{
+			"ID":     item.ID,
+			"txHash": item.InitialVerificationTxHash.Hex(),
+		}).Warn("Initial verification transaction disappeared, resetting item to 'CommitmentWitnessed'")
+
+		instructions := map[string]interface{}{
+			"status":                       store.CommitmentWitnessed,
+			"initial_verification_tx_hash": common.Hash{},
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case li.dbMessages <- store.NewDatabaseCmd(item, store.Update, instructions):
+		}
+	}
+
+	completeItems, err := li.beefyDB.GetItemsByStatus(store.CompleteVerificationTxSent)
+	if err != nil {
+		log.WithError(err).Error("Failure querying beefy DB for items by CompleteVerificationTxSent status")
+		return err
+	}
+
+	for _, item := range completeItems {
+		exists, err := li.transactionExists(ctx, item.CompleteVerificationTxHash)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		log.WithFields(logrus.Fields{
+			"ID":     item.ID,
+			"txHash": item.CompleteVerificationTxHash.Hex(),
+		}).Warn("Complete verification transaction disappeared, resetting item to 'InitialVerificationTxConfirmed'")
+
+		instructions := map[string]interface{}{
+			"status":                        store.InitialVerificationTxConfirmed,
+			"complete_verification_tx_hash": common.Hash{},
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case li.dbMessages <- store.NewDatabaseCmd(item, store.Update, instructions):
+		}
+	}
+
+	return nil
+}
+
+// transactionExists returns true if the transaction is either pending or included in the canonical chain
+func (li *BeefyEthereumListener) transactionExists(ctx context.Context, txHash common.Hash) (bool, error) {
+	_, _, err := li.ethereumConn.GetClient().TransactionByHash(ctx, txHash)
+	if err != nil {
+		if errors.Is(err, geth.NotFound) {
+			return false, nil
+		}
+		log.WithError(err).WithField("txHash", txHash.Hex()).Error("Failure querying transaction")
+		return false, err
+	}
+	return true, nil
+}
+
+// observeState updates the metrics tracking the light client and the relay database
+func (li *BeefyEthereumListener) observeState(ctx context.Context) {
+	latestBeefyBlock, err := li.beefyLightClient.ContractCaller.LatestBeefyBlock(&bind.CallOpts{
+		Pending: false,
+		Context: ctx,
+	})
+	if err != nil {
+		log.WithError(err).Warn("Failed to get latest beefy block from light client")
+	} else {
+		lightClientBeefyBlock.Set(float64(latestBeefyBlock))
+	}
+
+	counts, err := li.beefyDB.CountItemsByStatus()
+	if err != nil {
+		log.WithError(err).Warn("Failed to count beefy DB items")
+		return
+	}
+	for status, count := range counts {
+		databaseItems.WithLabelValues(status.String()).Set(float64(count))
+	}
+}
+
+func (li *BeefyEthereumListener) recordGasUsed(ctx context.Context, method string, txHash common.Hash) {
+	gas, err := li.ethereumConn.TransactionGasUsed(ctx, txHash)
+	if err != nil {
+		log.WithError(err).WithField("txHash", txHash.Hex()).Warn("Failed to get gas used by transaction")
+		return
+	}
+	gasUsed.WithLabelValues(method).Add(float64(gas))
+}
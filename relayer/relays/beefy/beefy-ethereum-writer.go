@@ -18,6 +18,7 @@ import (
 
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
 	"github.com/snowfork/snowbridge/relayer/contracts/beefylightclient"
+	"github.com/snowfork/snowbridge/relayer/health"
 	"github.com/snowfork/snowbridge/relayer/relays/beefy/store"
 
 	log "github.com/sirupsen/logrus"
@@ -28,6 +29,7 @@ type BeefyEthereumWriter struct {
 	ethereumConn     *ethereum.Connection
 	beefyDB          *store.Database
 	beefyLightClient *beefylightclient.Contract
+	simulator        *ethereum.Simulator
 	databaseMessages chan<- store.DatabaseCmd
 	beefyMessages    <-chan store.BeefyRelayInfo
 }
@@ -57,6 +59,12 @@ func (wr *BeefyEthereumWriter) Start(ctx context.Context, eg *errgroup.Group) er
 	}
 	wr.beefyLightClient = beefyLightClientContract
 
+	simulator, err := ethereum.NewSimulator(wr.ethereumConn, address, beefylightclient.ContractABI)
+	if err != nil {
+		return err
+	}
+	wr.simulator = simulator
+
 	eg.Go(func() error {
 		err := wr.writeMessagesLoop(ctx)
 		log.WithField("reason", err).Info("Shutting down ethereum writer")
@@ -95,6 +103,8 @@ func (wr *BeefyEthereumWriter) writeMessagesLoop(ctx context.Context) error {
 					return err
 				}
 			}
+			health.Progress("ethereum-writer")
+
 			// Rate-limit transaction sending to reduce the chance of transactions using the same pending nonce.
 			select {
 			case <-ctx.Done():
@@ -107,12 +117,12 @@ func (wr *BeefyEthereumWriter) writeMessagesLoop(ctx context.Context) error {
 
 func (wr *BeefyEthereumWriter) makeTxOpts(ctx context.Context) *bind.TransactOpts {
 	chainID := wr.ethereumConn.ChainID()
-	keypair := wr.ethereumConn.GetKP()
+	signer := wr.ethereumConn.Signer()
 
 	options := bind.TransactOpts{
-		From: keypair.CommonAddress(),
+		From: signer.CommonAddress(),
 		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
-			return types.SignTx(tx, types.NewLondonSigner(chainID), keypair.PrivateKey())
+			return signer.SignTx(ctx, tx, chainID)
 		},
 		Context: ctx,
 	}
@@ -171,6 +181,27 @@ func (wr *BeefyEthereumWriter) WriteNewSignatureCommitment(ctx context.Context,
 
 	options := wr.makeTxOpts(ctx)
 
+	err = wr.simulator.Simulate(ctx, options.From, "newSignatureCommitment", msg.CommitmentHash,
+		msg.ValidatorClaimsBitfield, msg.ValidatorSignatureCommitment,
+		msg.ValidatorPosition, msg.ValidatorPublicKey, msg.ValidatorPublicKeyMerkleProof)
+	if err != nil {
+		var revertErr *ethereum.RevertError
+		if !errors.As(err, &revertErr) {
+			return err
+		}
+		logger := log.WithFields(logrus.Fields{
+			"BlockNumber": beefyJustification.SignedCommitment.Commitment.BlockNumber,
+			"reason":      revertErr.Reason,
+			"class":       revertErr.Class.String(),
+		})
+		if revertErr.Class == ethereum.RevertNonceUsed {
+			logger.Info("Commitment has already been superseded, skipping")
+		} else {
+			logger.Error("ALERT: New Signature Commitment fails simulation and will not be sent")
+		}
+		return nil
+	}
+
 	tx, err := contract.NewSignatureCommitment(options, msg.CommitmentHash,
 		msg.ValidatorClaimsBitfield, msg.ValidatorSignatureCommitment,
 		msg.ValidatorPosition, msg.ValidatorPublicKey, msg.ValidatorPublicKeyMerkleProof)
@@ -178,6 +209,7 @@ func (wr *BeefyEthereumWriter) WriteNewSignatureCommitment(ctx context.Context,
 		log.WithError(err).Error("Failed to submit transaction")
 		return err
 	}
+	transactionsSent.WithLabelValues(newSignatureCommitmentMethod).Inc()
 
 	var pkProofHex []string
 	for _, proofItem := range msg.ValidatorPublicKeyMerkleProof {
@@ -193,14 +225,26 @@ func (wr *BeefyEthereumWriter) WriteNewSignatureCommitment(ctx context.Context,
 		"BlockNumber":                       beefyJustification.SignedCommitment.Commitment.BlockNumber,
 	}).Info("New Signature Commitment transaction submitted")
 
-	log.Info("1: Creating item in Database with status 'InitialVerificationTxSent'")
-	info.Status = store.InitialVerificationTxSent
-	info.InitialVerificationTxHash = tx.Hash()
+	var cmd store.DatabaseCmd
+	if info.ID == 0 {
+		log.Info("1: Creating item in Database with status 'InitialVerificationTxSent'")
+		info.Status = store.InitialVerificationTxSent
+		info.InitialVerificationTxHash = tx.Hash()
+		cmd = store.NewDatabaseCmd(&info, store.Create, nil)
+	} else {
+		// Item was reset to 'CommitmentWitnessed' after already having been persisted
+		log.Info("1: Updating item status from 'CommitmentWitnessed' to 'InitialVerificationTxSent'")
+		instructions := map[string]interface{}{
+			"status":                       store.InitialVerificationTxSent,
+			"initial_verification_tx_hash": tx.Hash(),
+		}
+		cmd = store.NewDatabaseCmd(&info, store.Update, instructions)
+	}
 
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
-	case wr.databaseMessages <- store.NewDatabaseCmd(&info, store.Create, nil):
+	case wr.databaseMessages <- cmd:
 	}
 
 	return nil
@@ -265,20 +309,33 @@ func (wr *BeefyEthereumWriter) WriteCompleteSignatureCommitment(ctx context.Cont
 		return err
 	}
 
+	simplifiedProof := beefylightclient.SimplifiedMMRProof{
+		MerkleProofItems:         msg.SimplifiedProof.MerkleProofItems,
+		MerkleProofOrderBitField: msg.SimplifiedProof.MerkleProofOrderBitField,
+	}
+
+	err = wr.simulator.Simulate(ctx, options.From, "completeSignatureCommitment",
+		msg.ID, msg.Commitment, validatorProof, msg.LatestMMRLeaf, simplifiedProof)
+	if err != nil {
+		var revertErr *ethereum.RevertError
+		if !errors.As(err, &revertErr) {
+			return err
+		}
+		return wr.handleCompleteSignatureCommitmentRevert(ctx, info, revertErr)
+	}
+
 	tx, err := contract.CompleteSignatureCommitment(options,
 		msg.ID,
 		msg.Commitment,
 		validatorProof,
 		msg.LatestMMRLeaf,
-		beefylightclient.SimplifiedMMRProof{
-			MerkleProofItems:         msg.SimplifiedProof.MerkleProofItems,
-			MerkleProofOrderBitField: msg.SimplifiedProof.MerkleProofOrderBitField,
-		})
+		simplifiedProof)
 
 	if err != nil {
 		log.WithError(err).Error("Failed to submit transaction")
 		return err
 	}
+	transactionsSent.WithLabelValues(completeSignatureCommitmentMethod).Inc()
 
 	log.WithFields(logrus.Fields{
 		"txHash": tx.Hash().Hex(),
@@ -299,3 +356,35 @@ func (wr *BeefyEthereumWriter) WriteCompleteSignatureCommitment(ctx context.Cont
 
 	return nil
 }
+
+// handleCompleteSignatureCommitmentRevert decides what to do with an item whose CompleteSignatureCommitment
+// transaction fails simulation. Unless the item is removed, it remains in status InitialVerificationTxConfirmed
+// and is retried with a fresh random bitfield and proofs upon the next Ethereum header.
+func (wr *BeefyEthereumWriter) handleCompleteSignatureCommitmentRevert(
+	ctx context.Context,
+	info store.BeefyRelayInfo,
+	revertErr *ethereum.RevertError,
+) error {
+	logger := log.WithFields(logrus.Fields{
+		"ID":         info.ID,
+		"contractID": info.ContractID,
+		"reason":     revertErr.Reason,
+		"class":      revertErr.Class.String(),
+	})
+
+	switch revertErr.Class {
+	case ethereum.RevertNonceUsed:
+		logger.Info("Commitment has already been superseded, removing item from database")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case wr.databaseMessages <- store.NewDatabaseCmd(&info, store.Delete, nil):
+		}
+	case ethereum.RevertInvalidProof:
+		logger.Error("ALERT: Complete Signature Commitment fails simulation and will not be sent")
+	default:
+		logger.Warn("Complete Signature Commitment fails simulation, will retry with regenerated proofs")
+	}
+
+	return nil
+}
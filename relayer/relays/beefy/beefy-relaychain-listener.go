@@ -12,6 +12,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
+	"github.com/snowfork/snowbridge/relayer/health"
 	"github.com/snowfork/snowbridge/relayer/relays/beefy/store"
 	"github.com/snowfork/snowbridge/relayer/substrate"
 	"github.com/snowfork/snowbridge/relayer/crypto/merkle"
@@ -38,6 +39,16 @@ func NewBeefyRelaychainListener(
 }
 
 func (li *BeefyRelaychainListener) Start(ctx context.Context, eg *errgroup.Group, startingBeefyBlock uint64) error {
+	eg.Go(func() error {
+		err := li.watchFinalizedHeads(ctx)
+		if err != nil {
+			log.WithError(err).Error("Failed to watch finalized relay chain heads")
+		}
+		return err
+	})
+
+	synced := health.ExpectUntil(ctx, "beefy justification sync")
+
 	eg.Go(func() error {
 		defer close(li.beefyMessages)
 
@@ -48,6 +59,7 @@ func (li *BeefyRelaychainListener) Start(ctx context.Context, eg *errgroup.Group
 			}
 			return err
 		}
+		synced.Satisfy()
 
 		err = li.subBeefyJustifications(ctx)
 		log.WithField("reason", err).Info("Shutting down polkadot listener")
@@ -59,6 +71,27 @@ func (li *BeefyRelaychainListener) Start(ctx context.Context, eg *errgroup.Group
 	return nil
 }
 
+// watchFinalizedHeads tracks the finalized head of the relay chain for the metrics
+func (li *BeefyRelaychainListener) watchFinalizedHeads(ctx context.Context) error {
+	sub, err := li.relaychainConn.API().RPC.Chain.SubscribeFinalizedHeads()
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			li.relaychainConn.Disconnected()
+			return err
+		case header := <-sub.Chan():
+			relaychainFinalizedBlock.Set(float64(header.Number))
+		}
+	}
+}
+
 func (li *BeefyRelaychainListener) syncBeefyJustifications(ctx context.Context, latestBeefyBlock uint64) error {
 	beefySkipPeriod := li.config.Source.BeefySkipPeriod
 
@@ -295,11 +328,7 @@ func (li *BeefyRelaychainListener) getBeefyAuthorities(blockNumber uint64) ([]co
 	// Convert from beefy authorities to ethereum addresses
 	var authorityEthereumAddresses []common.Address
 	for _, authority := range authorities {
-		pub, err := crypto.DecompressPubkey(authority[:])
-		if err != nil {
-			return nil, err
-		}
-		ethereumAddress := crypto.PubkeyToAddress(*pub)
+		ethereumAddress, err := AuthorityAddress(authority)
 		if err != nil {
 			return nil, err
 		}
@@ -308,3 +337,13 @@ func (li *BeefyRelaychainListener) getBeefyAuthorities(blockNumber uint64) ([]co
 
 	return authorityEthereumAddresses, nil
 }
+
+// AuthorityAddress returns the Ethereum address which the BEEFY light client uses for the
+// authority with the given compressed ECDSA public key
+func AuthorityAddress(authority [33]uint8) (common.Address, error) {
+	pub, err := crypto.DecompressPubkey(authority[:])
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
@@ -5,8 +5,15 @@ import (
 )
 
 type Config struct {
-	Source SourceConfig `mapstructure:"source"`
-	Sink   SinkConfig   `mapstructure:"sink"`
+	Source   SourceConfig   `mapstructure:"source"`
+	Sink     SinkConfig     `mapstructure:"sink"`
+	Database DatabaseConfig `mapstructure:"database"`
+}
+
+type DatabaseConfig struct {
+	// Path to the sqlite database file. If empty, a temporary database
+	// is used which is deleted on shutdown.
+	Path string `mapstructure:"path"`
 }
 
 type SourceConfig struct {
@@ -7,8 +7,9 @@ import (
 
 	"github.com/snowfork/snowbridge/relayer/chain"
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/pool"
 	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
-	"github.com/snowfork/snowbridge/relayer/crypto/secp256k1"
+	"github.com/snowfork/snowbridge/relayer/crypto"
 	"github.com/snowfork/snowbridge/relayer/relays/beefy/store"
 
 	log "github.com/sirupsen/logrus"
@@ -16,6 +17,7 @@ import (
 
 type Relay struct {
 	config                  *Config
+	connections             *pool.Pool
 	relaychainConn          *relaychain.Connection
 	ethereumConn            *ethereum.Connection
 	beefyEthereumListener   *BeefyEthereumListener
@@ -26,19 +28,26 @@ type Relay struct {
 	ethHeaders              chan chain.Header
 }
 
-func NewRelay(config *Config, ethereumKeypair *secp256k1.Keypair) (*Relay, error) {
+// NewRelay creates the relay. Its relay chain connection is taken from the pool, so that it can be
+// shared with other relays.
+func NewRelay(config *Config, ethereumSigner crypto.Signer, connections *pool.Pool) (*Relay, error) {
 	log.Info("Relay created")
 
 	dbMessages := make(chan store.DatabaseCmd)
-	beefyDB := store.NewDatabase(dbMessages)
+	var beefyDB *store.Database
+	if config.Database.Path != "" {
+		beefyDB = store.NewPersistentDatabase(config.Database.Path, dbMessages)
+	} else {
+		beefyDB = store.NewDatabase(dbMessages)
+	}
 
 	err := beefyDB.Initialize()
 	if err != nil {
 		return nil, err
 	}
 
-	relaychainConn := relaychain.NewConnection(config.Source.Polkadot.Endpoint)
-	ethereumConn := ethereum.NewConnection(config.Sink.Ethereum.Endpoint, ethereumKeypair)
+	relaychainConn := connections.Relaychain(config.Source.Polkadot.Endpoint)
+	ethereumConn := ethereum.NewConnection(config.Sink.Ethereum.Endpoint, ethereumSigner)
 
 	beefyMessages := make(chan store.BeefyRelayInfo)
 	ethHeaders := make(chan chain.Header)
@@ -57,6 +66,7 @@ func NewRelay(config *Config, ethereumKeypair *secp256k1.Keypair) (*Relay, error
 
 	return &Relay{
 		config:                  config,
+		connections:             connections,
 		relaychainConn:          relaychainConn,
 		beefyEthereumListener:   beefyEthereumListener,
 		ethereumConn:            ethereumConn,
@@ -69,13 +79,22 @@ func NewRelay(config *Config, ethereumKeypair *secp256k1.Keypair) (*Relay, error
 }
 
 func (relay *Relay) Start(ctx context.Context, eg *errgroup.Group) error {
+	// Each instance of the relay has its own signing connection. The pooled relay chain connection is
+	// left open for the other relays.
+	eg.Go(func() error {
+		<-ctx.Done()
+		relay.ethereumConn.Close()
+		return nil
+	})
+
+	// Closes the database when the relay stops
 	err := relay.beefyDB.Start(ctx, eg)
 	if err != nil {
 		log.WithError(err).Error("Failed to start database")
 		return err
 	}
 
-	err = relay.relaychainConn.Connect(ctx)
+	err = relay.connections.Connect(ctx, relay.relaychainConn)
 	if err != nil {
 		return err
 	}
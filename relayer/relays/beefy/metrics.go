@@ -0,0 +1,48 @@
+package beefy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	lightClientBeefyBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "beefy_relay",
+		Name:      "latest_beefy_block",
+		Help:      "Latest relay chain block verified by the BEEFY light client on Ethereum.",
+	})
+
+	relaychainFinalizedBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "beefy_relay",
+		Name:      "relaychain_finalized_block",
+		Help:      "Latest finalized relay chain block.",
+	})
+
+	databaseItems = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "beefy_relay",
+		Name:      "database_items",
+		Help:      "Commitments held in the relay database, by status.",
+	}, []string{"status"})
+
+	transactionsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "beefy_relay",
+		Name:      "transactions_sent_total",
+		Help:      "Transactions sent to the BEEFY light client on Ethereum, by method.",
+	}, []string{"method"})
+
+	gasUsed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "beefy_relay",
+		Name:      "gas_used_total",
+		Help:      "Gas used by the transactions sent to the BEEFY light client on Ethereum, by method.",
+	}, []string{"method"})
+)
+
+const (
+	newSignatureCommitmentMethod      = "newSignatureCommitment"
+	completeSignatureCommitmentMethod = "completeSignatureCommitment"
+)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"sync"
@@ -26,6 +27,32 @@ const (
 	CompleteVerificationTxSent     Status = iota // 4
 )
 
+// Statuses lists every status in the order an item passes through them
+var Statuses = []Status{
+	CommitmentWitnessed,
+	InitialVerificationTxSent,
+	InitialVerificationTxConfirmed,
+	ReadyToComplete,
+	CompleteVerificationTxSent,
+}
+
+func (s Status) String() string {
+	switch s {
+	case CommitmentWitnessed:
+		return "CommitmentWitnessed"
+	case InitialVerificationTxSent:
+		return "InitialVerificationTxSent"
+	case InitialVerificationTxConfirmed:
+		return "InitialVerificationTxConfirmed"
+	case ReadyToComplete:
+		return "ReadyToComplete"
+	case CompleteVerificationTxSent:
+		return "CompleteVerificationTxSent"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
 type BeefyRelayInfo struct {
 	gorm.Model
 	ValidatorAddresses         []byte
@@ -80,6 +107,19 @@ func (BeefyRelayInfo) TableName() string {
 	return "beefy_relay_info"
 }
 
+// ListenerState holds the progress of the ethereum listener, so that it carries on from where it
+// stopped after a restart. The table has a single row.
+type ListenerState struct {
+	ID                 uint `gorm:"primary_key"`
+	LastProcessedBlock uint64
+}
+
+func (ListenerState) TableName() string {
+	return "beefy_listener_state"
+}
+
+const listenerStateID = 1
+
 type CmdType int
 
 const (
@@ -106,6 +146,8 @@ type Database struct {
 	Path     string
 	DB       *gorm.DB
 	messages <-chan DatabaseCmd
+	// Temporary databases are deleted when the write loop shuts down
+	temporary bool
 }
 
 func NewDatabase(messages <-chan DatabaseCmd) *Database {
@@ -116,14 +158,29 @@ func NewDatabase(messages <-chan DatabaseCmd) *Database {
 	}
 }
 
+// NewPersistentDatabase creates a database backed by the sqlite file at path. Unlike
+// the temporary database created by NewDatabase, the file is kept across restarts.
+func NewPersistentDatabase(path string, messages <-chan DatabaseCmd) *Database {
+	return &Database{
+		Path:     path,
+		DB:       nil,
+		messages: messages,
+	}
+}
+
 func (d *Database) Initialize() error {
-	tmpfile, err := ioutil.TempFile("", "beefy.*.db")
-	if err != nil {
-		return nil
+	path := d.Path
+	if path == "" {
+		tmpfile, err := ioutil.TempFile("", "beefy.*.db")
+		if err != nil {
+			return nil
+		}
+		tmpfile.Close()
+		path = tmpfile.Name()
+		d.temporary = true
 	}
-	tmpfile.Close()
 
-	db, err := gorm.Open("sqlite3", tmpfile.Name())
+	db, err := gorm.Open("sqlite3", path)
 	if err != nil {
 		return err
 	}
@@ -134,12 +191,25 @@ func (d *Database) Initialize() error {
 		db.Model(&beefyRelayInfo)
 	}
 
-	d.Path = tmpfile.Name()
+	var listenerState ListenerState
+	if !db.HasTable(&listenerState) {
+		db.CreateTable(&listenerState)
+	}
+
+	d.Path = path
 	d.DB = db
 
 	return nil
 }
 
+func (d *Database) Close() error {
+	sqlDB := d.DB.DB()
+	if sqlDB == nil {
+		return nil
+	}
+	return sqlDB.Close()
+}
+
 func (d *Database) Start(ctx context.Context, eg *errgroup.Group) error {
 	eg.Go(func() error {
 		var err1, err2 error
@@ -154,9 +224,11 @@ func (d *Database) Start(ctx context.Context, eg *errgroup.Group) error {
 				log.WithError(err2).Error("Unable to close DB connection")
 			}
 
-			err2 = os.Remove(d.Path)
-			if err2 != nil {
-				log.WithError(err2).Error("Unable to delete DB file")
+			if d.temporary {
+				err2 = os.Remove(d.Path)
+				if err2 != nil {
+					log.WithError(err2).Error("Unable to delete DB file")
+				}
 			}
 		}
 
@@ -226,6 +298,49 @@ func (d *Database) GetItemsByStatus(status Status) ([]*BeefyRelayInfo, error) {
 	return items, nil
 }
 
+// CountItemsByStatus returns the number of items in the database with each status. Every status
+// is present in the result, including those without items.
+func (d *Database) CountItemsByStatus() (map[Status]int, error) {
+	var rows []struct {
+		Status Status
+		Count  int
+	}
+	err := d.DB.Model(&BeefyRelayInfo{}).Select("status, count(*) as count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[Status]int, len(Statuses))
+	for _, status := range Statuses {
+		counts[status] = 0
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// GetItems returns all items in the database, ordered by their row ID
+func (d *Database) GetItems() ([]*BeefyRelayInfo, error) {
+	items := make([]*BeefyRelayInfo, 0)
+	err := d.DB.Order("id").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetItemByRowID returns the item with the given database row ID. Not to be confused with
+// GetItemByID, which queries by the ID assigned by the BeefyLightClient contract.
+func (d *Database) GetItemByRowID(id uint) (*BeefyRelayInfo, error) {
+	var item BeefyRelayInfo
+	err := d.DB.Take(&item, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
 func (d *Database) GetItemByID(id int64) (*BeefyRelayInfo, error) {
 	var item BeefyRelayInfo
 	err := d.DB.Take(&item, "contract_id = ?", id).Error
@@ -249,3 +364,41 @@ func (d *Database) GetItemByCompleteVerificationTxHash(txHash common.Hash) *Beef
 	d.DB.Take(&item, "complete_verification_tx_hash = ?", txHash)
 	return &item
 }
+
+// ResetItem moves an item back to status CommitmentWitnessed, clearing all state
+// collected by the light client verification, so that the relay starts again from the
+// initial verification. Only to be used while the relay is stopped.
+func (d *Database) ResetItem(item *BeefyRelayInfo) error {
+	instructions := map[string]interface{}{
+		"status":                        CommitmentWitnessed,
+		"contract_id":                   0,
+		"initial_verification_tx_hash":  common.Hash{},
+		"complete_on_block":             0,
+		"random_seed":                   common.Hash{},
+		"complete_verification_tx_hash": common.Hash{},
+	}
+	return d.DB.Model(item).Updates(instructions).Error
+}
+
+// DeleteItem removes an item from the database. Only to be used while the relay is stopped.
+func (d *Database) DeleteItem(item *BeefyRelayInfo) error {
+	return d.DB.Delete(item, item.ID).Error
+}
+
+// GetLastProcessedBlock returns the last Ethereum block for which the ethereum listener processed
+// light client events. Returns false if it hasn't processed any block yet.
+func (d *Database) GetLastProcessedBlock() (uint64, bool, error) {
+	var state ListenerState
+	err := d.DB.Take(&state, "id = ?", listenerStateID).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return state.LastProcessedBlock, true, nil
+}
+
+func (d *Database) PutLastProcessedBlock(blockNumber uint64) error {
+	return d.DB.Save(&ListenerState{ID: listenerStateID, LastProcessedBlock: blockNumber}).Error
+}
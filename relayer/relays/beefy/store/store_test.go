@@ -82,6 +82,24 @@ func (suite *StoreTestSuite) TestGetItemsByStatus() {
 	suite.Equal(beefyItem.Status, store.CommitmentWitnessed)
 }
 
+func (suite *StoreTestSuite) TestCountItemsByStatus() {
+	witnessed := loadSampleBeefyRelayInfo()
+	sent := loadSampleBeefyRelayInfo()
+	sent.Status = store.InitialVerificationTxSent
+
+	suite.messages <- store.NewDatabaseCmd(&witnessed, store.Create, nil)
+	suite.messages <- store.NewDatabaseCmd(&sent, store.Create, nil)
+
+	time.Sleep(2 * time.Second)
+
+	counts, err := suite.database.CountItemsByStatus()
+	suite.Nil(err)
+	suite.Equal(len(store.Statuses), len(counts))
+	suite.Equal(1, counts[store.CommitmentWitnessed])
+	suite.Equal(1, counts[store.InitialVerificationTxSent])
+	suite.Equal(0, counts[store.ReadyToComplete])
+}
+
 func (suite *StoreTestSuite) TestGetItemByID() {
 	id := int64(55)
 	item := loadSampleBeefyRelayInfo()
@@ -185,6 +203,66 @@ func (suite *StoreTestSuite) TestDeleteItem() {
 	suite.Equal(uint64(0), deletedItem.CompleteOnBlock)
 }
 
+func (suite *StoreTestSuite) TestResetItem() {
+	id := int64(99)
+	item := loadSampleBeefyRelayInfo()
+	item.ContractID = id
+
+	// Pass create command to write loop
+	createCmd := store.NewDatabaseCmd(&item, store.Create, nil)
+	suite.messages <- createCmd
+
+	time.Sleep(2 * time.Second)
+
+	hash := common.BytesToHash([]byte("0x25451A4de12dcCc2D166922fA938E900fCc4ED24"))
+	instructions := map[string]interface{}{
+		"status":                        store.CompleteVerificationTxSent,
+		"complete_verification_tx_hash": hash,
+	}
+	updateCmd := store.NewDatabaseCmd(&item, store.Update, instructions)
+	suite.messages <- updateCmd
+
+	time.Sleep(2 * time.Second)
+
+	foundItem, err := suite.database.GetItemByRowID(item.ID)
+	suite.Nil(err)
+	suite.Equal(store.CompleteVerificationTxSent, foundItem.Status)
+
+	err = suite.database.ResetItem(foundItem)
+	suite.Nil(err)
+
+	resetItem, err := suite.database.GetItemByRowID(item.ID)
+	suite.Nil(err)
+	suite.Equal(store.CommitmentWitnessed, resetItem.Status)
+	suite.Equal(int64(0), resetItem.ContractID)
+	suite.Equal(uint64(0), resetItem.CompleteOnBlock)
+	suite.Equal(common.Hash{}, resetItem.CompleteVerificationTxHash)
+	suite.Equal(item.SignedCommitment, resetItem.SignedCommitment)
+
+	err = suite.database.DeleteItem(resetItem)
+	suite.Nil(err)
+
+	items, err := suite.database.GetItems()
+	suite.Nil(err)
+	for _, i := range items {
+		suite.NotEqual(item.ID, i.ID)
+	}
+}
+
+func (suite *StoreTestSuite) TestLastProcessedBlock() {
+	_, ok, err := suite.database.GetLastProcessedBlock()
+	suite.Nil(err)
+	suite.False(ok)
+
+	suite.Nil(suite.database.PutLastProcessedBlock(100))
+	suite.Nil(suite.database.PutLastProcessedBlock(105))
+
+	blockNumber, ok, err := suite.database.GetLastProcessedBlock()
+	suite.Nil(err)
+	suite.True(ok)
+	suite.Equal(uint64(105), blockNumber)
+}
+
 func loadSampleBeefyRelayInfo() store.BeefyRelayInfo {
 	// Sample BEEFY commitment: validator addresses
 	beefyValidatorAddresses := []common.Address{
@@ -0,0 +1,244 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Number of blocks queried at a time when searching backwards for undelivered messages
+	catchupBlockWindow = 1000
+	// Maximum number of undelivered messages submitted in a single batch
+	catchupBatchSize = 20
+)
+
+// InboundNonces holds the nonces of the last messages accepted by the inbound channels on the parachain
+type InboundNonces struct {
+	Basic        uint64
+	Incentivized uint64
+}
+
+func (n InboundNonces) forChannel(name string) uint64 {
+	if name == "incentivized" {
+		return n.Incentivized
+	}
+	return n.Basic
+}
+
+type messageEvent struct {
+	nonce  uint64
+	source common.Address
+	log    *etypes.Log
+}
+
+// outboundChannel wraps the outbound channel contracts so that they can be reconciled in the same way
+type outboundChannel struct {
+	name           string
+	inboundNonce   uint64
+	nonce          func(opts *bind.CallOpts) (uint64, error)
+	filterMessages func(opts *bind.FilterOpts) ([]messageEvent, error)
+}
+
+func (li *EthereumListener) outboundChannels() []outboundChannel {
+	return []outboundChannel{
+		{
+			name:           "basic",
+			inboundNonce:   li.inboundNonces.Basic,
+			nonce:          li.basicOutboundChannel.Nonce,
+			filterMessages: li.filterBasicMessages,
+		},
+		{
+			name:           "incentivized",
+			inboundNonce:   li.inboundNonces.Incentivized,
+			nonce:          li.incentivizedOutboundChannel.Nonce,
+			filterMessages: li.filterIncentivizedMessages,
+		},
+	}
+}
+
+// Resubmits the messages which were committed on Ethereum in blocks whose headers have already been
+// imported by the parachain, but which were never accepted by the parachain inbound channels.
+// Messages in later blocks are relayed together with their headers.
+func (li *EthereumListener) relayMissedMessages(ctx context.Context, headerCache *ethereum.HeaderCache) error {
+	// Events are fetched for the blocks descendantsUntilFinal behind each header relayed from initBlockHeight
+	if li.initBlockHeight < li.descendantsUntilFinal+1 {
+		return nil
+	}
+	lastBlock := li.initBlockHeight - li.descendantsUntilFinal - 1
+	firstBlock := li.config.ChannelsDeploymentBlock
+	if firstBlock > lastBlock {
+		return nil
+	}
+
+	var events []*etypes.Log
+	for _, channel := range li.outboundChannels() {
+		missed, err := li.findMissedMessages(ctx, channel, firstBlock, lastBlock)
+		if err != nil {
+			return err
+		}
+		events = append(events, li.filterEvents(channel.name, missed)...)
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"count":     len(events),
+		"lastBlock": lastBlock,
+	}).Info("Resubmitting undelivered messages")
+
+	for len(events) > 0 {
+		batchSize := catchupBatchSize
+		if len(events) < batchSize {
+			batchSize = len(events)
+		}
+
+		messages, err := li.makeOutgoingMessages(ctx, headerCache, events[:batchSize])
+		if err != nil {
+			return err
+		}
+		events = events[batchSize:]
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case li.payloads <- ParachainPayload{Messages: messages}:
+		}
+	}
+
+	return nil
+}
+
+// Returns the Message events, in nonce order, for the messages in the given channel which were committed
+// from firstBlock to lastBlock but have not been accepted by the parachain. The search goes backwards
+// from lastBlock, and stops at firstBlock even if the first undelivered message wasn't found.
+func (li *EthereumListener) findMissedMessages(
+	ctx context.Context,
+	channel outboundChannel,
+	firstBlock uint64,
+	lastBlock uint64,
+) ([]messageEvent, error) {
+	outboundNonce, err := channel.nonce(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		log.WithError(err).WithField("channel", channel.name).Error("Failed to get outbound channel nonce")
+		return nil, err
+	}
+	channelNonce.WithLabelValues(channel.name, "ethereum").Set(float64(outboundNonce))
+
+	logger := log.WithFields(log.Fields{
+		"channel":       channel.name,
+		"outboundNonce": outboundNonce,
+		"inboundNonce":  channel.inboundNonce,
+	})
+
+	if outboundNonce <= channel.inboundNonce {
+		logger.Debug("Channel nonces are reconciled")
+		return nil, nil
+	}
+
+	logger.Info("Parachain has not accepted all messages, searching for undelivered messages")
+
+	var found []messageEvent
+	end := lastBlock
+	for {
+		start := firstBlock
+		if end >= firstBlock+catchupBlockWindow {
+			start = end - catchupBlockWindow + 1
+		}
+
+		events, err := channel.filterMessages(&bind.FilterOpts{Start: start, End: &end, Context: ctx})
+		if err != nil {
+			logger.WithError(err).Error("Failure fetching event logs")
+			return nil, err
+		}
+
+		reachedInboundNonce := false
+		for _, event := range events {
+			if event.nonce > channel.inboundNonce {
+				found = append(found, event)
+			}
+			if event.nonce <= channel.inboundNonce+1 {
+				reachedInboundNonce = true
+			}
+		}
+
+		if reachedInboundNonce || start == firstBlock {
+			break
+		}
+		end = start - 1
+	}
+
+	if len(found) == 0 {
+		logger.Info("No undelivered messages in blocks with imported headers")
+		return nil, nil
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].nonce < found[j].nonce })
+
+	for i, event := range found {
+		expectedNonce := channel.inboundNonce + uint64(i) + 1
+		if event.nonce != expectedNonce {
+			return nil, fmt.Errorf("%s channel message with nonce %d not found", channel.name, expectedNonce)
+		}
+	}
+
+	logger.WithFields(log.Fields{
+		"firstNonce": found[0].nonce,
+		"lastNonce":  found[len(found)-1].nonce,
+	}).Info("Found undelivered messages")
+
+	return found, nil
+}
+
+func (li *EthereumListener) filterBasicMessages(options *bind.FilterOpts) ([]messageEvent, error) {
+	iter, err := li.basicOutboundChannel.FilterMessage(options)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var events []messageEvent
+	for iter.Next() {
+		raw := iter.Event.Raw
+		events = append(events, messageEvent{nonce: iter.Event.Nonce, source: iter.Event.Source, log: &raw})
+	}
+
+	err = iter.Error()
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (li *EthereumListener) filterIncentivizedMessages(options *bind.FilterOpts) ([]messageEvent, error) {
+	iter, err := li.incentivizedOutboundChannel.FilterMessage(options)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var events []messageEvent
+	for iter.Next() {
+		raw := iter.Event.Raw
+		events = append(events, messageEvent{nonce: iter.Event.Nonce, source: iter.Event.Source, log: &raw})
+	}
+
+	err = iter.Error()
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
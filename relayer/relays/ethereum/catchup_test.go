@@ -0,0 +1,91 @@
+package ethereum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// Simulates an outbound channel which emitted one message per block, starting at block 1
+func makeTestChannel(outboundNonce uint64, inboundNonce uint64, queries *int) outboundChannel {
+	return outboundChannel{
+		name:         "basic",
+		inboundNonce: inboundNonce,
+		nonce: func(_ *bind.CallOpts) (uint64, error) {
+			return outboundNonce, nil
+		},
+		filterMessages: func(opts *bind.FilterOpts) ([]messageEvent, error) {
+			*queries++
+			var events []messageEvent
+			for block := opts.Start; block <= *opts.End; block++ {
+				if block == 0 || block > outboundNonce {
+					continue
+				}
+				events = append(events, messageEvent{
+					nonce: block,
+					log:   &etypes.Log{BlockNumber: block},
+				})
+			}
+			return events, nil
+		},
+	}
+}
+
+func TestFindMissedMessages(t *testing.T) {
+	li := &EthereumListener{}
+
+	queries := 0
+	logs, err := li.findMissedMessages(context.Background(), makeTestChannel(3000, 1500, &queries), 0, 2600)
+	assert.NoError(t, err)
+	assert.Equal(t, 1100, len(logs))
+	assert.Equal(t, uint64(1501), logs[0].log.BlockNumber)
+	assert.Equal(t, uint64(2600), logs[len(logs)-1].log.BlockNumber)
+	// The search stops once the first undelivered message is found
+	assert.Equal(t, 2, queries)
+}
+
+func TestFindMissedMessagesReconciled(t *testing.T) {
+	li := &EthereumListener{}
+
+	queries := 0
+	logs, err := li.findMissedMessages(context.Background(), makeTestChannel(30, 30, &queries), 0, 2500)
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+	assert.Equal(t, 0, queries)
+}
+
+func TestFindMissedMessagesAfterLastBlock(t *testing.T) {
+	li := &EthereumListener{}
+
+	// Undelivered messages in blocks after the last block are relayed with their headers
+	queries := 0
+	logs, err := li.findMissedMessages(context.Background(), makeTestChannel(30, 20, &queries), 0, 20)
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+}
+
+func TestFindMissedMessagesStopsAtFirstBlock(t *testing.T) {
+	li := &EthereumListener{}
+
+	// Nothing was delivered and the first message is after the last block, so the search never finds it
+	queries := 0
+	channel := outboundChannel{
+		name:         "basic",
+		inboundNonce: 0,
+		nonce: func(_ *bind.CallOpts) (uint64, error) {
+			return 5, nil
+		},
+		filterMessages: func(opts *bind.FilterOpts) ([]messageEvent, error) {
+			queries++
+			assert.GreaterOrEqual(t, opts.Start, uint64(4500))
+			return nil, nil
+		},
+	}
+	logs, err := li.findMissedMessages(context.Background(), channel, 4500, 7200)
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+	assert.Equal(t, 3, queries)
+}
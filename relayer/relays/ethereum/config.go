@@ -2,11 +2,13 @@ package ethereum
 
 import (
 	"github.com/snowfork/snowbridge/relayer/config"
+	"github.com/snowfork/snowbridge/relayer/relays/filter"
 )
 
 type Config struct {
-	Source SourceConfig `mapstructure:"source"`
-	Sink   SinkConfig   `mapstructure:"sink"`
+	Source SourceConfig  `mapstructure:"source"`
+	Sink   SinkConfig    `mapstructure:"sink"`
+	Filter filter.Config `mapstructure:"filter"`
 }
 
 type SourceConfig struct {
@@ -14,6 +16,9 @@ type SourceConfig struct {
 	DataDir               string                `mapstructure:"data-dir"`
 	DescendantsUntilFinal uint64                `mapstructure:"descendants-until-final"`
 	Contracts             ContractsConfig       `mapstructure:"contracts"`
+
+	// Block at which the outbound channels were deployed. Searches for undelivered messages don't go back further.
+	ChannelsDeploymentBlock uint64 `mapstructure:"channels-deployment-block"`
 }
 
 type ContractsConfig struct {
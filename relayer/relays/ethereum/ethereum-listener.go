@@ -21,7 +21,9 @@ import (
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
 	"github.com/snowfork/snowbridge/relayer/contracts/basic"
 	"github.com/snowfork/snowbridge/relayer/contracts/incentivized"
+	"github.com/snowfork/snowbridge/relayer/health"
 	"github.com/snowfork/snowbridge/relayer/relays/ethereum/syncer"
+	"github.com/snowfork/snowbridge/relayer/relays/filter"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -39,6 +41,11 @@ type EthereumListener struct {
 	headerSyncer                *syncer.Syncer
 	initBlockHeight             uint64
 	descendantsUntilFinal       uint64
+	inboundNonces               InboundNonces
+	queryInboundNonces          func() (InboundNonces, error)
+	filter                      *filter.Filter
+	// Highest nonce dropped in each channel which the parachain is not yet known to have accepted
+	undelivered map[string]uint64
 }
 
 func NewEthereumListener(
@@ -46,6 +53,9 @@ func NewEthereumListener(
 	conn *ethereum.Connection,
 	initBlockHeight uint64,
 	descendantsUntilFinal uint64,
+	inboundNonces InboundNonces,
+	queryInboundNonces func() (InboundNonces, error),
+	filter *filter.Filter,
 ) *EthereumListener {
 	return &EthereumListener{
 		ethashDataDir:               filepath.Join(config.DataDir, "ethash-data"),
@@ -58,6 +68,10 @@ func NewEthereumListener(
 		headerSyncer:                nil,
 		initBlockHeight:             initBlockHeight,
 		descendantsUntilFinal:       descendantsUntilFinal,
+		inboundNonces:               inboundNonces,
+		queryInboundNonces:          queryInboundNonces,
+		filter:                      filter,
+		undelivered:                 make(map[string]uint64),
 	}
 }
 
@@ -122,9 +136,15 @@ func (li *EthereumListener) Start(
 		return nil, err
 	}
 
+	caughtUp := health.ExpectUntil(ctx, "ethereum message catch-up")
+
 	eg.Go(func() error {
 		defer close(li.payloads)
-		err := li.processEventsAndHeaders(ctx, headers, headerCache)
+		err := li.relayMissedMessages(ctx, headerCache)
+		if err == nil {
+			caughtUp.Satisfy()
+			err = li.processEventsAndHeaders(ctx, headers, headerCache)
+		}
 		log.WithField("reason", err).Info("Shutting down ethereum listener")
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
@@ -158,6 +178,8 @@ func (li *EthereumListener) processEventsAndHeaders(
 			if err != nil {
 				return err
 			}
+			latestEthereumHeader.Set(float64(header.Number.Uint64()))
+			health.Progress("ethereum-listener")
 
 			// Don't attempt to forward events prior to genesis block
 			if li.descendantsUntilFinal > header.Number.Uint64() {
@@ -170,19 +192,19 @@ func (li *EthereumListener) processEventsAndHeaders(
 
 			filterOptions := bind.FilterOpts{Start: finalizedBlockNumber, End: &finalizedBlockNumber, Context: ctx}
 
-			basicEvents, err := li.queryBasicEvents(li.basicOutboundChannel, &filterOptions)
+			err = li.resumeChannels()
 			if err != nil {
-				log.WithError(err).Error("Failure fetching event logs")
 				return err
 			}
-			events = append(events, basicEvents...)
 
-			incentivizedEvents, err := li.queryIncentivizedEvents(li.incentivizedOutboundChannel, &filterOptions)
-			if err != nil {
-				log.WithError(err).Error("Failure fetching event logs")
-				return err
+			for _, channel := range li.outboundChannels() {
+				channelEvents, err := channel.filterMessages(&filterOptions)
+				if err != nil {
+					log.WithError(err).Error("Failure fetching event logs")
+					return err
+				}
+				events = append(events, li.filterEvents(channel.name, channelEvents)...)
 			}
-			events = append(events, incentivizedEvents...)
 
 			messages, err := li.makeOutgoingMessages(ctx, headerCache, events)
 			if err != nil {
@@ -199,48 +221,70 @@ func (li *EthereumListener) processEventsAndHeaders(
 	}
 }
 
-func (li *EthereumListener) queryBasicEvents(contract *basic.BasicOutboundChannel, options *bind.FilterOpts) ([]*etypes.Log, error) {
-	var events []*etypes.Log
-
-	iter, err := contract.FilterMessage(options)
-	if err != nil {
-		return nil, err
-	}
-
-	for {
-		more := iter.Next()
-		if !more {
-			err = iter.Error()
-			if err != nil {
-				return nil, err
-			}
-			break
+// Drops the events for messages which are rejected by the filter. The parachain only accepts the next
+// nonce in each channel, so once a message is dropped, later messages in the channel are dropped as well
+// until another relayer has delivered the dropped messages. A relayer with a filter therefore depends on
+// other relayers to fill the gaps it leaves.
+func (li *EthereumListener) filterEvents(channel string, events []messageEvent) []*etypes.Log {
+	var allowed []*etypes.Log
+	for _, event := range events {
+		source := event.source
+		relay, reason := li.filter.Allows(&filter.Message{
+			Channel: channel,
+			Source:  &source,
+		})
+
+		messagesFiltered.WithLabelValues(channel, filter.DecisionLabel(relay)).Inc()
+		channelNonce.WithLabelValues(channel, "ethereum").Set(float64(event.nonce))
+
+		logger := log.WithFields(logrus.Fields{
+			"channel":     channel,
+			"nonce":       event.nonce,
+			"source":      source.Hex(),
+			"blockNumber": event.log.BlockNumber,
+			"reason":      reason,
+		})
+		if !relay {
+			logger.Info("Dropping message rejected by filter")
+			li.undelivered[channel] = event.nonce
+			continue
+		}
+		if gap, ok := li.undelivered[channel]; ok {
+			logger.WithField("undeliveredNonce", gap).Warn("Dropping message which follows an undelivered message")
+			li.undelivered[channel] = event.nonce
+			continue
 		}
-		events = append(events, &iter.Event.Raw)
+		logger.Debug("Message accepted by filter")
+
+		allowed = append(allowed, event.log)
 	}
-	return events, nil
+	return allowed
 }
 
-func (li *EthereumListener) queryIncentivizedEvents(contract *incentivized.IncentivizedOutboundChannel, options *bind.FilterOpts) ([]*etypes.Log, error) {
-	var events []*etypes.Log
+// Resumes relaying messages in the channels where the parachain has accepted every dropped message
+func (li *EthereumListener) resumeChannels() error {
+	if len(li.undelivered) == 0 {
+		return nil
+	}
 
-	iter, err := contract.FilterMessage(options)
+	nonces, err := li.queryInboundNonces()
 	if err != nil {
-		return nil, err
+		log.WithError(err).Error("Failed to query inbound channel nonces")
+		return err
 	}
 
-	for {
-		more := iter.Next()
-		if !more {
-			err = iter.Error()
-			if err != nil {
-				return nil, err
-			}
-			break
+	for channel, gap := range li.undelivered {
+		if nonces.forChannel(channel) < gap {
+			continue
 		}
-		events = append(events, &iter.Event.Raw)
+		log.WithFields(logrus.Fields{
+			"channel": channel,
+			"nonce":   gap,
+		}).Info("Dropped messages were delivered by another relayer, resuming channel")
+		delete(li.undelivered, channel)
 	}
-	return events, nil
+
+	return nil
 }
 
 func (li *EthereumListener) makeOutgoingMessages(
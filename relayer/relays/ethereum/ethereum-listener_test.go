@@ -0,0 +1,66 @@
+package ethereum
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snowfork/snowbridge/relayer/relays/filter"
+)
+
+var (
+	deniedSource  = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	allowedSource = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func makeTestEvent(nonce uint64, source common.Address) messageEvent {
+	return messageEvent{
+		nonce:  nonce,
+		source: source,
+		log:    &etypes.Log{BlockNumber: nonce},
+	}
+}
+
+func TestFilterEventsBlocksChannelAfterDeniedMessage(t *testing.T) {
+	messageFilter, err := filter.New(&filter.Config{
+		Deny: []filter.RuleConfig{{Source: deniedSource.Hex()}},
+	})
+	require.NoError(t, err)
+
+	inboundNonces := InboundNonces{Basic: 1}
+	li := &EthereumListener{
+		filter:      messageFilter,
+		undelivered: make(map[string]uint64),
+		queryInboundNonces: func() (InboundNonces, error) {
+			return inboundNonces, nil
+		},
+	}
+
+	logs := li.filterEvents("basic", []messageEvent{
+		makeTestEvent(2, allowedSource),
+		makeTestEvent(3, deniedSource),
+		makeTestEvent(4, allowedSource),
+	})
+	// The allowed message after the denied one would fail with an invalid nonce
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, uint64(2), logs[0].BlockNumber)
+
+	// Other channels are unaffected
+	logs = li.filterEvents("incentivized", []messageEvent{makeTestEvent(1, allowedSource)})
+	assert.Equal(t, 1, len(logs))
+
+	// The channel stays blocked until the parachain has accepted every dropped message
+	inboundNonces.Basic = 3
+	assert.NoError(t, li.resumeChannels())
+	logs = li.filterEvents("basic", []messageEvent{makeTestEvent(5, allowedSource)})
+	assert.Empty(t, logs)
+
+	inboundNonces.Basic = 5
+	assert.NoError(t, li.resumeChannels())
+	logs = li.filterEvents("basic", []messageEvent{makeTestEvent(6, allowedSource)})
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, uint64(6), logs[0].BlockNumber)
+}
@@ -11,33 +11,47 @@ import (
 	"github.com/snowfork/go-substrate-rpc-client/v3/types"
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
 	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/chain/pool"
 	"github.com/snowfork/snowbridge/relayer/crypto/sr25519"
+	"github.com/snowfork/snowbridge/relayer/relays/filter"
 
 	log "github.com/sirupsen/logrus"
 )
 
 type Relay struct {
-	config     *Config
-	keypair    *sr25519.Keypair
-	ethconn    *ethereum.Connection
-	paraconn   *parachain.Connection
+	config      *Config
+	keypair     *sr25519.Keypair
+	connections *pool.Pool
+	ethconn     *ethereum.Connection
+	paraconn    *parachain.Connection
 }
 
+// NewRelay creates the relay. Its Ethereum connection is taken from the pool, so that it can be
+// shared with other relays.
 func NewRelay(
 	config *Config,
 	keypair *sr25519.Keypair,
+	connections *pool.Pool,
 ) *Relay {
 	return &Relay{
-		config:  config,
-		keypair: keypair,
+		config:      config,
+		keypair:     keypair,
+		connections: connections,
 	}
 }
 
 func (r *Relay) Start(ctx context.Context, eg *errgroup.Group) error {
-	r.ethconn = ethereum.NewConnection(r.config.Source.Ethereum.Endpoint, nil)
+	r.ethconn = r.connections.Ethereum(r.config.Source.Ethereum.Endpoint)
 	r.paraconn = parachain.NewConnection(r.config.Sink.Parachain.Endpoint, r.keypair.AsKeyringPair())
 
-	err := r.ethconn.Connect(ctx)
+	// The parachain connection signs, so each instance of the relay has its own
+	eg.Go(func() error {
+		<-ctx.Done()
+		r.paraconn.Close()
+		return nil
+	})
+
+	err := r.connections.Connect(ctx, r.ethconn)
 	if err != nil {
 		return err
 	}
@@ -53,11 +67,25 @@ func (r *Relay) Start(ctx context.Context, eg *errgroup.Group) error {
 	}
 	log.WithField("blockNumber", finalizedBlockNumber).Debug("Retrieved finalized block number from parachain")
 
+	inboundNonces, err := QueryInboundNonces(r.paraconn)
+	if err != nil {
+		return err
+	}
+	observeInboundNonces(inboundNonces)
+
+	messageFilter, err := filter.New(&r.config.Filter)
+	if err != nil {
+		return err
+	}
+
 	listener := NewEthereumListener(
 		&r.config.Source,
 		r.ethconn,
 		finalizedBlockNumber + 1,
 		uint64(r.config.Source.DescendantsUntilFinal),
+		inboundNonces,
+		func() (InboundNonces, error) { return QueryInboundNonces(r.paraconn) },
+		messageFilter,
 	)
 
 	payloads, err := listener.Start(ctx, eg)
@@ -92,3 +120,36 @@ func (r *Relay) queryFinalizedBlockNumber() (uint64, error) {
 
 	return uint64(finalizedHeader.Number), nil
 }
+
+// QueryInboundNonces returns the nonces of the last messages accepted by the inbound channels on the parachain
+func QueryInboundNonces(conn *parachain.Connection) (InboundNonces, error) {
+	basicNonce, err := queryInboundNonce(conn, "BasicInboundModule")
+	if err != nil {
+		return InboundNonces{}, err
+	}
+
+	incentivizedNonce, err := queryInboundNonce(conn, "IncentivizedInboundModule")
+	if err != nil {
+		return InboundNonces{}, err
+	}
+
+	return InboundNonces{
+		Basic:        basicNonce,
+		Incentivized: incentivizedNonce,
+	}, nil
+}
+
+func queryInboundNonce(conn *parachain.Connection, module string) (uint64, error) {
+	storageKey, err := types.CreateStorageKey(conn.Metadata(), module, "Nonce", nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var nonce types.U64
+	_, err = conn.API().RPC.State.GetStorageLatest(storageKey, &nonce)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(nonce), nil
+}
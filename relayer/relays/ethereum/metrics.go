@@ -0,0 +1,55 @@
+package ethereum
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	messagesFiltered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "ethereum_relay",
+		Name:      "filter_decisions_total",
+		Help:      "Messages checked against the message filter, by decision.",
+	}, []string{"channel", "decision"})
+
+	latestEthereumHeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "ethereum_relay",
+		Name:      "latest_ethereum_header",
+		Help:      "Number of the latest Ethereum header processed by the relay.",
+	})
+
+	latestImportedHeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "ethereum_relay",
+		Name:      "latest_imported_header",
+		Help:      "Number of the latest Ethereum header imported into the parachain light client by the relay.",
+	})
+
+	channelNonce = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "ethereum_relay",
+		Name:      "channel_nonce",
+		Help:      "Latest known nonce of the outbound channel on Ethereum and the inbound channel on the parachain.",
+	}, []string{"channel", "chain"})
+
+	extrinsicsSubmitted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "ethereum_relay",
+		Name:      "extrinsics_submitted_total",
+		Help:      "Extrinsics submitted to the parachain, by result.",
+	}, []string{"result"})
+)
+
+func observeInboundNonces(nonces InboundNonces) {
+	channelNonce.WithLabelValues("basic", "parachain").Set(float64(nonces.Basic))
+	channelNonce.WithLabelValues("incentivized", "parachain").Set(float64(nonces.Incentivized))
+}
+
+func submitResultLabel(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
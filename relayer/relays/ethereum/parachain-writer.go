@@ -13,6 +13,8 @@ import (
 	"github.com/snowfork/snowbridge/relayer/chain"
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
 	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/retry"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -82,7 +84,7 @@ func (wr *ParachainWriter) queryAccountNonce() (uint32, error) {
 		return 0, err
 	}
 	if !ok {
-		return 0, fmt.Errorf("no account info found for %s", wr.conn.Keypair().URI)
+		return 0, retry.Fatal(fmt.Errorf("no account info found for %s", wr.conn.Keypair().Address))
 	}
 
 	return uint32(accountInfo.Nonce), nil
@@ -98,20 +100,22 @@ func (wr *ParachainWriter) writeLoop(ctx context.Context) error {
 				return nil
 			}
 
-			header := payload.Header.HeaderData.(ethereum.Header)
+			fields := logrus.Fields{
+				"messageCount": len(payload.Messages),
+			}
+			if payload.Header != nil {
+				header := payload.Header.HeaderData.(ethereum.Header)
+				fields["blockNumber"] = header.Fields.Number
+			}
+
 			err := wr.WritePayload(ctx, &payload)
 			if err != nil {
-				log.WithError(err).WithFields(logrus.Fields{
-					"blockNumber":  header.Fields.Number,
-					"messageCount": len(payload.Messages),
-				}).Error("Failure submitting header and messages to Substrate")
+				log.WithError(err).WithFields(fields).Error("Failure submitting header and messages to Substrate")
 				return err
 			}
 
-			log.WithFields(logrus.Fields{
-				"blockNumber":  header.Fields.Number,
-				"messageCount": len(payload.Messages),
-			}).Info("Submitted transaction to Substrate")
+			log.WithFields(fields).Info("Submitted transaction to Substrate")
+			health.Progress("parachain-writer")
 		}
 	}
 }
@@ -161,6 +165,7 @@ func (wr *ParachainWriter) write(
 		"nonce": wr.nonce,
 	}).Info("Submitting transaction")
 	err = wr.pool.WaitForSubmitAndWatch(ctx, &extI, onFinalized)
+	extrinsicsSubmitted.WithLabelValues(submitResultLabel(err)).Inc()
 	if err != nil {
 		log.WithError(err).WithField("nonce", wr.nonce).Debug("Failed to submit extrinsic")
 		return err
@@ -171,30 +176,33 @@ func (wr *ParachainWriter) write(
 	return nil
 }
 
+// WritePayload submits the header and messages in the payload. Payloads without a header carry messages
+// from blocks whose headers were already imported. The header is imported in its own extrinsic, so that
+// messages which are rejected by the inbound channels don't hold up the import of headers.
 func (wr *ParachainWriter) WritePayload(ctx context.Context, payload *ParachainPayload) error {
-	var calls []types.Call
-	call, err := wr.makeHeaderImportCall(payload.Header)
-	if err != nil {
-		return err
-	}
-	calls = append(calls, call)
-
-	for _, msg := range payload.Messages {
-		call, err := wr.makeMessageSubmitCall(msg)
+	if payload.Header != nil {
+		err := wr.writeHeader(ctx, payload.Header)
 		if err != nil {
 			return err
 		}
-		calls = append(calls, call)
 	}
 
-	call, err = types.NewCall(wr.conn.Metadata(), "Utility.batch_all", calls)
+	if len(payload.Messages) > 0 {
+		return wr.writeMessages(ctx, payload.Messages)
+	}
+
+	return nil
+}
+
+func (wr *ParachainWriter) writeHeader(ctx context.Context, header *chain.Header) error {
+	call, err := wr.makeHeaderImportCall(header)
 	if err != nil {
 		return err
 	}
 
 	onFinalized := func(_ types.Hash) error {
 		// Confirm that the header import was successful
-		header := payload.Header.HeaderData.(ethereum.Header)
+		header := header.HeaderData.(ethereum.Header)
 		hash := header.ID().Hash
 		imported, err := wr.queryImportedHeaderExists(hash)
 		if err != nil {
@@ -203,6 +211,35 @@ func (wr *ParachainWriter) WritePayload(ctx context.Context, payload *ParachainP
 		if !imported {
 			return fmt.Errorf("Header import failed for header %s", hash.Hex())
 		}
+		latestImportedHeader.Set(float64(header.Fields.Number))
+		return nil
+	}
+
+	return wr.write(ctx, call, onFinalized)
+}
+
+func (wr *ParachainWriter) writeMessages(ctx context.Context, messages []*chain.EthereumOutboundMessage) error {
+	var calls []types.Call
+	for _, msg := range messages {
+		call, err := wr.makeMessageSubmitCall(msg)
+		if err != nil {
+			return err
+		}
+		calls = append(calls, call)
+	}
+
+	call, err := types.NewCall(wr.conn.Metadata(), "Utility.batch_all", calls)
+	if err != nil {
+		return err
+	}
+
+	onFinalized := func(_ types.Hash) error {
+		nonces, err := QueryInboundNonces(wr.conn)
+		if err != nil {
+			log.WithError(err).Warn("Failed to query inbound channel nonces")
+		} else {
+			observeInboundNonces(nonces)
+		}
 		return nil
 	}
 
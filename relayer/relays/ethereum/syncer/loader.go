@@ -10,7 +10,6 @@ import (
 	"github.com/ethereum/go-ethereum"
 	gethCommon "github.com/ethereum/go-ethereum/common"
 	gethTypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type HeaderLoader interface {
@@ -20,10 +19,10 @@ type HeaderLoader interface {
 }
 
 type DefaultHeaderLoader struct {
-	client *ethclient.Client
+	client HeaderLoader
 }
 
-func NewHeaderLoader(client *ethclient.Client) *DefaultHeaderLoader {
+func NewHeaderLoader(client HeaderLoader) *DefaultHeaderLoader {
 	return &DefaultHeaderLoader{client: client}
 }
 
@@ -14,6 +14,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/snowfork/snowbridge/relayer/health"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -36,6 +38,8 @@ type Syncer struct {
 	loader                HeaderLoader
 	newHeaders            chan *gethTypes.Header
 	oldHeaders            chan *gethTypes.Header
+	// Satisfied once all finalized headers since the initial height have been forwarded
+	synced *health.Condition
 }
 
 func NewSyncer(descendantsUntilFinal uint64, loader HeaderLoader) *Syncer {
@@ -59,6 +63,7 @@ func (s *Syncer) StartSync(
 		height:             0,
 	}
 
+	s.synced = health.ExpectUntil(ctx, "ethereum header sync")
 	s.headers = make(chan *gethTypes.Header, 5)
 	s.newHeaders = make(chan *gethTypes.Header)
 	s.oldHeaders = make(chan *gethTypes.Header)
@@ -134,6 +139,7 @@ func (s *Syncer) fetchFinalizedHeaders(ctx context.Context, initBlockHeight uint
 			lbi.Unlock()
 
 			log.WithField("blockNumber", syncedUpUntil).Debug("Done retrieving finalized headers")
+			s.synced.Satisfy()
 
 			break
 		}
@@ -0,0 +1,161 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+type Config struct {
+	// If not empty, only messages matching one of these rules are relayed
+	Allow []RuleConfig `mapstructure:"allow"`
+	// Messages matching one of these rules are not relayed
+	Deny []RuleConfig `mapstructure:"deny"`
+}
+
+// RuleConfig matches messages on all of its non-empty fields
+type RuleConfig struct {
+	// Channel name, i.e. "basic" or "incentivized"
+	Channel string `mapstructure:"channel"`
+	// Address of the application on Ethereum which sent the message
+	Source string `mapstructure:"source"`
+	// Address of the application on Ethereum to which the message is sent
+	Target string `mapstructure:"target"`
+}
+
+// Message describes a message being relayed. Addresses which are not known are nil.
+type Message struct {
+	Channel string
+	Source  *common.Address
+	Target  *common.Address
+}
+
+type rule struct {
+	channel string
+	source  *common.Address
+	target  *common.Address
+}
+
+func (r *rule) matches(msg *Message) bool {
+	if r.channel != "" && r.channel != msg.Channel {
+		return false
+	}
+	if r.source != nil && (msg.Source == nil || *r.source != *msg.Source) {
+		return false
+	}
+	if r.target != nil && (msg.Target == nil || *r.target != *msg.Target) {
+		return false
+	}
+	return true
+}
+
+func (r *rule) String() string {
+	var fields []string
+	if r.channel != "" {
+		fields = append(fields, "channel="+r.channel)
+	}
+	if r.source != nil {
+		fields = append(fields, "source="+r.source.Hex())
+	}
+	if r.target != nil {
+		fields = append(fields, "target="+r.target.Hex())
+	}
+	return strings.Join(fields, " ")
+}
+
+// Filter decides which messages are relayed. Deny rules take precedence over allow rules.
+type Filter struct {
+	allow []rule
+	deny  []rule
+}
+
+func New(config *Config) (*Filter, error) {
+	allow, err := parseRules(config.Allow)
+	if err != nil {
+		return nil, err
+	}
+
+	deny, err := parseRules(config.Deny)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Filter{
+		allow: allow,
+		deny:  deny,
+	}, nil
+}
+
+func parseRules(configs []RuleConfig) ([]rule, error) {
+	var rules []rule
+	for _, config := range configs {
+		switch config.Channel {
+		case "", "basic", "incentivized":
+		default:
+			return nil, retry.Fatal(fmt.Errorf("unknown channel '%s' in filter rule", config.Channel))
+		}
+
+		source, err := parseAddress(config.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		target, err := parseAddress(config.Target)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule{
+			channel: config.Channel,
+			source:  source,
+			target:  target,
+		})
+	}
+	return rules, nil
+}
+
+func parseAddress(value string) (*common.Address, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(value) {
+		return nil, retry.Fatal(fmt.Errorf("invalid address '%s' in filter rule", value))
+	}
+	address := common.HexToAddress(value)
+	return &address, nil
+}
+
+// Allows returns whether the message should be relayed, and the reason for the decision
+func (f *Filter) Allows(msg *Message) (bool, string) {
+	for _, r := range f.deny {
+		if r.matches(msg) {
+			return false, fmt.Sprintf("matches deny rule (%s)", r.String())
+		}
+	}
+
+	if len(f.allow) == 0 {
+		return true, "no allow rules"
+	}
+
+	for _, r := range f.allow {
+		if r.matches(msg) {
+			return true, fmt.Sprintf("matches allow rule (%s)", r.String())
+		}
+	}
+
+	return false, "matches no allow rule"
+}
+
+// DecisionLabel returns the label used for filter decisions in metrics
+func DecisionLabel(allowed bool) string {
+	if allowed {
+		return "relay"
+	}
+	return "drop"
+}
@@ -0,0 +1,77 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/snowfork/snowbridge/relayer/relays/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ethApp   = common.HexToAddress("0x774667629726ec1FaBEbCEc0D9139bD1C8f72a23")
+	erc20App = common.HexToAddress("0x83428c7db9815f482a39a1715684dCF755021997")
+	dotApp   = common.HexToAddress("0xB1185EDE04202fE62D38F5db72F71e38Ff3E8305")
+)
+
+func newFilter(t *testing.T, config filter.Config) *filter.Filter {
+	f, err := filter.New(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestEmptyFilterAllowsAll(t *testing.T) {
+	f := newFilter(t, filter.Config{})
+
+	allowed, _ := f.Allows(&filter.Message{Channel: "basic", Target: &dotApp})
+	assert.True(t, allowed)
+}
+
+func TestAllowRules(t *testing.T) {
+	f := newFilter(t, filter.Config{
+		Allow: []filter.RuleConfig{
+			{Target: ethApp.Hex()},
+			{Channel: "incentivized", Target: erc20App.Hex()},
+		},
+	})
+
+	allowed, _ := f.Allows(&filter.Message{Channel: "basic", Target: &ethApp})
+	assert.True(t, allowed)
+
+	allowed, _ = f.Allows(&filter.Message{Channel: "incentivized", Target: &erc20App})
+	assert.True(t, allowed)
+
+	allowed, _ = f.Allows(&filter.Message{Channel: "basic", Target: &erc20App})
+	assert.False(t, allowed)
+
+	allowed, _ = f.Allows(&filter.Message{Channel: "basic", Target: &dotApp})
+	assert.False(t, allowed)
+
+	// Messages without a known target don't match target rules
+	allowed, _ = f.Allows(&filter.Message{Channel: "basic", Source: &ethApp})
+	assert.False(t, allowed)
+}
+
+func TestDenyRulesTakePrecedence(t *testing.T) {
+	f := newFilter(t, filter.Config{
+		Allow: []filter.RuleConfig{{Channel: "basic"}},
+		Deny:  []filter.RuleConfig{{Source: dotApp.Hex()}},
+	})
+
+	allowed, _ := f.Allows(&filter.Message{Channel: "basic", Source: &ethApp})
+	assert.True(t, allowed)
+
+	allowed, reason := f.Allows(&filter.Message{Channel: "basic", Source: &dotApp})
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "deny")
+}
+
+func TestInvalidRules(t *testing.T) {
+	_, err := filter.New(&filter.Config{Allow: []filter.RuleConfig{{Channel: "fast"}}})
+	assert.Error(t, err)
+
+	_, err = filter.New(&filter.Config{Deny: []filter.RuleConfig{{Target: "0x1234"}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package parachain
+
+import (
+	"encoding/hex"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/contracts/basic"
+
+	gsrpcTypes "github.com/snowfork/go-substrate-rpc-client/v3/types"
+)
+
+// Index of the basic channel in the runtime's ChannelId enum
+const basicChannelID parachain.ChannelID = 0
+
+func init() {
+	registerChannel(basicChannelID, "basic", newBasicChannel)
+}
+
+type BasicChannel struct {
+	contract  *basic.BasicInboundChannel
+	simulator *ethereum.Simulator
+}
+
+func newBasicChannel(conn *ethereum.Connection, address common.Address) (Channel, error) {
+	contract, err := basic.NewBasicInboundChannel(address, conn.GetClient())
+	if err != nil {
+		return nil, err
+	}
+
+	simulator, err := ethereum.NewSimulator(conn, address, basic.BasicInboundChannelABI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BasicChannel{
+		contract:  contract,
+		simulator: simulator,
+	}, nil
+}
+
+func (ch *BasicChannel) ID() parachain.ChannelID {
+	return basicChannelID
+}
+
+func (ch *BasicChannel) Name() string {
+	return "basic"
+}
+
+func (ch *BasicChannel) OutboundModule() string {
+	return "BasicOutboundModule"
+}
+
+func (ch *BasicChannel) InboundNonce(opts *bind.CallOpts) (uint64, error) {
+	return ch.contract.Nonce(opts)
+}
+
+func (ch *BasicChannel) decodeMessages(data []byte) ([]basic.BasicInboundChannelMessage, error) {
+	var outboundMessages []parachain.BasicOutboundChannelMessage
+	err := gsrpcTypes.DecodeFromBytes(data, &outboundMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []basic.BasicInboundChannelMessage
+	for _, m := range outboundMessages {
+		messages = append(messages,
+			basic.BasicInboundChannelMessage{
+				Target:  m.Target,
+				Nonce:   m.Nonce,
+				Payload: m.Payload,
+			},
+		)
+	}
+	return messages, nil
+}
+
+func (ch *BasicChannel) DecodeNonces(data []byte) ([]uint64, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonces []uint64
+	for _, m := range messages {
+		nonces = append(nonces, m.Nonce)
+	}
+	return nonces, nil
+}
+
+func (ch *BasicChannel) DecodeTargets(data []byte) ([]common.Address, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []common.Address
+	for _, m := range messages {
+		targets = append(targets, m.Target)
+	}
+	return targets, nil
+}
+
+func (ch *BasicChannel) LogMessages(data []byte) (interface{}, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var messagesLog []BasicInboundChannelMessageLog
+	for _, item := range messages {
+		messagesLog = append(messagesLog, BasicInboundChannelMessageLog{
+			Target:   item.Target,
+			Nonce:    item.Nonce,
+			Payload:  "0x" + hex.EncodeToString(item.Payload),
+			Transfer: describeTransfer(item.Payload),
+		})
+	}
+	return messagesLog, nil
+}
+
+func (ch *BasicChannel) Submit(opts *bind.TransactOpts, data []byte, proof *ParachainProof) (*types.Transaction, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	paraVerifyInput := basic.ParachainLightClientParachainVerifyInput{
+		OwnParachainHeadPrefixBytes: proof.ParaVerifyInput.OwnParachainHeadPrefixBytes,
+		OwnParachainHeadSuffixBytes: proof.ParaVerifyInput.OwnParachainHeadSuffixBytes,
+		ParachainHeadProof:          basic.ParachainLightClientParachainHeadProof(proof.ParaVerifyInput.ParachainHeadProof),
+	}
+	beefyMMRLeafPartial := basic.ParachainLightClientBeefyMMRLeafPartial(proof.BeefyMMRLeafPartial)
+	simplifiedMMRProof := basic.SimplifiedMMRProof(proof.SimplifiedMMRProof)
+
+	err = ch.simulator.Simulate(opts.Context, opts.From, "submit",
+		messages, paraVerifyInput, beefyMMRLeafPartial, simplifiedMMRProof)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.contract.Submit(opts, messages, paraVerifyInput,
+		beefyMMRLeafPartial, simplifiedMMRProof)
+}
+
+func (ch *BasicChannel) FilterMessageDispatched(opts *bind.FilterOpts) ([]MessageDispatched, error) {
+	iter, err := ch.contract.FilterMessageDispatched(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var events []MessageDispatched
+	for iter.Next() {
+		events = append(events, MessageDispatched{
+			Nonce:       iter.Event.Nonce,
+			Result:      iter.Event.Result,
+			TxHash:      iter.Event.Raw.TxHash,
+			BlockNumber: iter.Event.Raw.BlockNumber,
+		})
+	}
+
+	err = iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -3,6 +3,7 @@ package parachain
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -14,6 +15,9 @@ import (
 	"github.com/snowfork/snowbridge/relayer/chain/parachain"
 	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
 	"github.com/snowfork/snowbridge/relayer/contracts/beefylightclient"
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain/store"
+	"github.com/snowfork/snowbridge/relayer/retry"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -26,6 +30,11 @@ type BeefyListener struct {
 	parachainConnection *parachain.Connection
 	paraID              uint32
 	messages            chan<- MessagePackage
+	index               *store.Database
+	inclusionIndex      *InclusionIndex
+	channels            Channels
+	commitmentCache     map[uint64]*blockCommitments
+	commitmentCacheLock sync.Mutex
 }
 
 func NewBeefyListener(
@@ -34,6 +43,7 @@ func NewBeefyListener(
 	relaychainConn *relaychain.Connection,
 	parachainConnection *parachain.Connection,
 	messages chan<- MessagePackage,
+	index *store.Database,
 ) *BeefyListener {
 	return &BeefyListener{
 		config:              config,
@@ -41,6 +51,8 @@ func NewBeefyListener(
 		relaychainConn:      relaychainConn,
 		parachainConnection: parachainConnection,
 		messages:            messages,
+		index:               index,
+		commitmentCache:     make(map[uint64]*blockCommitments),
 	}
 }
 
@@ -54,6 +66,12 @@ func (li *BeefyListener) Start(ctx context.Context, eg *errgroup.Group) error {
 	}
 	li.beefyLightClient = beefyLightClientContract
 
+	channels, err := NewChannels(li.ethereumConn, li.config.Contracts.InboundChannels)
+	if err != nil {
+		return err
+	}
+	li.channels = channels
+
 	// Fetch ParaId
 	storageKeyForParaID, err := types.CreateStorageKey(li.parachainConnection.Metadata(), "ParachainInfo", "ParachainId", nil, nil)
 	if err != nil {
@@ -68,12 +86,20 @@ func (li *BeefyListener) Start(ctx context.Context, eg *errgroup.Group) error {
 	}
 	if !ok {
 		log.Error("Expected parachain but chain does not provide a parachain ID")
-		return fmt.Errorf("invalid parachain")
+		return retry.Fatal(fmt.Errorf("invalid parachain"))
 	}
 
 	log.WithField("paraId", paraID).Info("Fetched parachain id")
 	li.paraID = paraID
 
+	li.inclusionIndex = NewInclusionIndex(li.relaychainConn, li.index, paraID)
+	err = li.inclusionIndex.Start(ctx, eg)
+	if err != nil {
+		return err
+	}
+
+	caughtUp := health.ExpectUntil(ctx, "parachain message catch-up")
+
 	eg.Go(func() error {
 		beefyBlockNumber, beefyBlockHash, err := li.fetchLatestBeefyBlock(ctx)
 		if err != nil {
@@ -120,6 +146,7 @@ func (li *BeefyListener) Start(ctx context.Context, eg *errgroup.Group) error {
 		if err != nil {
 			return err
 		}
+		caughtUp.Satisfy()
 
 		err = li.subBeefyJustifications(ctx)
 		return err
@@ -169,6 +196,7 @@ func (li *BeefyListener) subBeefyJustifications(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
+			health.Progress("beefy-listener")
 		}
 	}
 }
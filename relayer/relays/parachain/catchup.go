@@ -6,102 +6,75 @@ import (
 	"github.com/snowfork/snowbridge/relayer/crypto/merkle"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/snowfork/go-substrate-rpc-client/v3/types"
 	"github.com/snowfork/snowbridge/relayer/chain/parachain"
-	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
-	"github.com/snowfork/snowbridge/relayer/contracts/basic"
-	"github.com/snowfork/snowbridge/relayer/contracts/incentivized"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain/store"
+	"github.com/snowfork/snowbridge/relayer/retry"
+	"golang.org/x/sync/errgroup"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// Number of parachain blocks fetched concurrently when searching for lost commitments
+const defaultCatchupConcurrency = 8
+
 // Catches up by searching for and relaying all missed commitments before the given para block
 // This method creates proofs based on the mmr root at the specific given relaychainBlock and so
 // the proofs will need to be verified by the mmr root for that relay chain block
 func (li *BeefyListener) buildMissedMessagePackages(
 	ctx context.Context, relaychainBlock uint64, relaychainBlockHash types.Hash, paraBlock uint64, paraHash types.Hash) (
 	[]MessagePackage, error) {
-	basicContract, err := basic.NewBasicInboundChannel(common.HexToAddress(
-		li.config.Contracts.BasicInboundChannel),
-		li.ethereumConn.GetClient(),
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	incentivizedContract, err := incentivized.NewIncentivizedInboundChannel(common.HexToAddress(
-		li.config.Contracts.IncentivizedInboundChannel),
-		li.ethereumConn.GetClient(),
-	)
-	if err != nil {
-		return nil, err
-	}
-
 	options := bind.CallOpts{
 		Pending: true,
 		Context: ctx,
 	}
 
-	ethBasicNonce, err := basicContract.Nonce(&options)
-	if err != nil {
-		return nil, err
-	}
-	log.WithFields(log.Fields{
-		"nonce": ethBasicNonce,
-	}).Info("Checked latest nonce delivered to ethereum basic channel")
-
-	ethIncentivizedNonce, err := incentivizedContract.Nonce(&options)
-	if err != nil {
-		return nil, err
-	}
-	log.WithFields(log.Fields{
-		"nonce": ethIncentivizedNonce,
-	}).Info("Checked latest nonce delivered to ethereum incentivized channel")
-
-	paraBasicNonceKey, err := types.CreateStorageKey(li.parachainConnection.Metadata(), "BasicOutboundModule", "Nonce", nil, nil)
-	if err != nil {
-		log.Error(err)
-		return nil, err
-	}
-	var paraBasicNonce types.U64
-	ok, err := li.parachainConnection.API().RPC.State.GetStorage(paraBasicNonceKey, &paraBasicNonce, paraHash)
-	if err != nil {
-		log.Error(err)
-		return nil, err
-	}
-	if !ok {
-		paraBasicNonce = 0
-	}
-	log.WithFields(log.Fields{
-		"nonce": uint64(paraBasicNonce),
-	}).Info("Checked latest nonce generated by parachain basic channel")
+	noncesToFind := make(map[parachain.ChannelID]uint64, len(li.channels))
+	upToDate := true
+	for id, channel := range li.channels {
+		ethNonce, err := channel.InboundNonce(&options)
+		if err != nil {
+			return nil, err
+		}
+		log.WithFields(log.Fields{
+			"nonce":   ethNonce,
+			"channel": channel.Name(),
+		}).Info("Checked latest nonce delivered to ethereum channel")
+		channelNonce.WithLabelValues(channel.Name(), "ethereum").Set(float64(ethNonce))
 
-	paraIncentivizedNonceKey, err := types.CreateStorageKey(li.parachainConnection.Metadata(), "IncentivizedOutboundModule", "Nonce", nil, nil)
-	if err != nil {
-		log.Error(err)
-		return nil, err
-	}
-	var paraIncentivizedNonce types.U64
-	ok, err = li.parachainConnection.API().RPC.State.GetStorage(paraIncentivizedNonceKey, &paraIncentivizedNonce, paraHash)
-	if err != nil {
-		log.Error(err)
-		return nil, err
-	}
-	if !ok {
-		paraIncentivizedNonce = 0
+		paraNonceKey, err := types.CreateStorageKey(li.parachainConnection.Metadata(), channel.OutboundModule(), "Nonce", nil, nil)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		var paraNonce types.U64
+		ok, err := li.parachainConnection.API().RPC.State.GetStorage(paraNonceKey, &paraNonce, paraHash)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		if !ok {
+			paraNonce = 0
+		}
+		log.WithFields(log.Fields{
+			"nonce":   uint64(paraNonce),
+			"channel": channel.Name(),
+		}).Info("Checked latest nonce generated by parachain channel")
+		channelNonce.WithLabelValues(channel.Name(), "parachain").Set(float64(paraNonce))
+
+		noncesToFind[id] = ethNonce
+		if ethNonce != uint64(paraNonce) {
+			upToDate = false
+		}
 	}
-	log.WithFields(log.Fields{
-		"nonce": uint64(paraIncentivizedNonce),
-	}).Info("Checked latest nonce generated by parachain incentivized channel")
 
-	if ethBasicNonce == uint64(paraBasicNonce) && ethIncentivizedNonce == uint64(paraIncentivizedNonce) {
+	if upToDate {
 		return nil, nil
 	}
 
 	log.Info("Nonces are not all up to date - searching for lost commitments")
 
-	paraBlocks, err := li.searchForLostCommitments(paraBlock, ethBasicNonce, ethIncentivizedNonce)
+	paraBlocks, err := li.searchForLostCommitments(paraBlock, noncesToFind)
 	if err != nil {
 		return nil, err
 	}
@@ -142,6 +115,52 @@ func (li *BeefyListener) buildMissedMessagePackages(
 	return messagePackages, nil
 }
 
+// RebuildMessagePackage regenerates the proofs for the given message package against the MMR root
+// of the latest relay chain block verified by the light client
+func (li *BeefyListener) RebuildMessagePackage(ctx context.Context, msg *MessagePackage) (*MessagePackage, error) {
+	beefyBlockNumber, beefyBlockHash, err := li.fetchLatestBeefyBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	block := ParaBlockWithDigest{
+		BlockNumber: uint64(msg.paraHead.Number),
+		DigestItemsWithData: []DigestItemWithData{
+			{
+				DigestItem: parachain.AuxiliaryDigestItem{
+					IsCommitment: true,
+					AsCommitment: parachain.Commitment{
+						ChannelID: msg.channelID,
+						Hash:      msg.commitmentHash,
+					},
+				},
+				Data: msg.commitmentData,
+			},
+		},
+	}
+
+	blocksWithProofs, err := li.parablocksWithProofs([]ParaBlockWithDigest{block}, beefyBlockNumber, beefyBlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	messagePackages, err := CreateMessagePackages(blocksWithProofs, 0, li.paraID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messagePackages) != 1 {
+		return nil, fmt.Errorf("expected 1 rebuilt message package, got %d", len(messagePackages))
+	}
+
+	log.WithFields(log.Fields{
+		"commitmentHash":   msg.commitmentHash.Hex(),
+		"beefyBlockNumber": beefyBlockNumber,
+	}).Info("Rebuilt message package")
+
+	return &messagePackages[0], nil
+}
+
 // Takes a slice of parachain blocks and augments them with their respective
 // header, header proof and MMR proof at the given relay chain block mmr root
 func (li *BeefyListener) parablocksWithProofs(
@@ -150,53 +169,51 @@ func (li *BeefyListener) parablocksWithProofs(
 	latestRelaychainBlockHash types.Hash,
 ) ([]ParaBlockWithProofs, error) {
 	relayChainBlockNumber := latestRelayChainBlockNumber
-	var relayBlockHash types.Hash
-	var err error
 	var blocksWithProof []ParaBlockWithProofs
 	for _, block := range blocks {
-		var ownParaHead types.Header
-		var heads map[uint32]relaychain.ParaHead
-
-		// Loop back over relay chain blocks to find the one that finalized the given parachain block
-		for ownParaHead.Number != types.BlockNumber(block.BlockNumber) {
-			log.WithField("relayChainBlockNumber", relayChainBlockNumber).Info("Getting hash for relay chain block")
-			relayBlockHash, err = li.relaychainConn.API().RPC.Chain.GetBlockHash(uint64(relayChainBlockNumber))
-			if err != nil {
-				log.WithError(err).Error("Failed to get block hash")
-				return nil, err
-			}
+		inclusionBlockNumber, err := li.findInclusionBlock(block.BlockNumber, relayChainBlockNumber)
+		if err != nil {
+			return nil, err
+		}
 
-			log.WithField("relayBlockHash", relayBlockHash.Hex()).Info("Got relay chain blockhash")
-			heads, err = li.relaychainConn.FetchParaHeads(relayBlockHash)
-			if err != nil {
-				log.WithError(err).Error("Failed to get paraheads")
-				return nil, err
-			}
+		log.WithField("relayChainBlockNumber", inclusionBlockNumber).Info("Getting hash for relay chain block")
+		relayBlockHash, err := li.relaychainConn.API().RPC.Chain.GetBlockHash(inclusionBlockNumber)
+		if err != nil {
+			log.WithError(err).Error("Failed to get block hash")
+			return nil, err
+		}
 
-			log.WithFields(log.Fields{
-				"count": len(heads),
-			}).Info("Fetched para heads")
+		log.WithField("relayBlockHash", relayBlockHash.Hex()).Info("Got relay chain blockhash")
+		heads, err := li.relaychainConn.FetchParaHeads(relayBlockHash)
+		if err != nil {
+			log.WithError(err).Error("Failed to get paraheads")
+			return nil, err
+		}
 
-			if _, ok := heads[li.paraID]; !ok {
-				return nil, fmt.Errorf("chain is not a registered parachain")
-			}
+		log.WithFields(log.Fields{
+			"count": len(heads),
+		}).Info("Fetched para heads")
 
-			var header types.Header
-			if err := types.DecodeFromBytes(heads[li.paraID].Data, &header); err != nil {
-				log.WithError(err).Error("Failed to decode Header")
-				return nil, err
-			}
+		if _, ok := heads[li.paraID]; !ok {
+			return nil, retry.Fatal(fmt.Errorf("chain is not a registered parachain"))
+		}
 
-			ownParaHead = header
+		var ownParaHead types.Header
+		if err := types.DecodeFromBytes(heads[li.paraID].Data, &ownParaHead); err != nil {
+			log.WithError(err).Error("Failed to decode Header")
+			return nil, err
+		}
 
-			relayChainBlockNumber--
+		if ownParaHead.Number != types.BlockNumber(block.BlockNumber) {
+			return nil, fmt.Errorf("para head at relay chain block %d is %d, expected %d",
+				inclusionBlockNumber, ownParaHead.Number, block.BlockNumber)
 		}
 
-		// Note - relayChainBlockNumber will be one less than the actual block number we want
-		// due to the decrement at the end of the loop, so we increment by 1. Additionally,
-		// parachain merkle roots are created 1 block later than the actual parachain headers,
-		// so we increment twice.
-		mmrProof, err := li.relaychainConn.GetMMRLeafForBlock(uint64(relayChainBlockNumber+2), latestRelaychainBlockHash, li.config.Polkadot.BeefyStartingBlock)
+		// Earlier parachain blocks were included in earlier relay chain blocks
+		relayChainBlockNumber = inclusionBlockNumber - 1
+
+		// Parachain merkle roots are created 1 block later than the actual parachain headers
+		mmrProof, err := li.relaychainConn.GetMMRLeafForBlock(inclusionBlockNumber+1, latestRelaychainBlockHash, li.config.Polkadot.BeefyStartingBlock)
 		if err != nil {
 			log.WithError(err).Error("Failed to get mmr leaf")
 			return nil, err
@@ -230,7 +247,7 @@ func (li *BeefyListener) parablocksWithProofs(
 		}
 
 		if merkleProofData.Root.Hex() != mmrProof.Leaf.ParachainHeads.Hex() {
-			err = fmt.Errorf("MMR parachain merkle root does not match calculated parachain merkle root - calculated: %s, mmr: %s", merkleProofData.Root.String(), mmrProof.Leaf.ParachainHeads.Hex())
+			err = retry.Fatal(fmt.Errorf("MMR parachain merkle root does not match calculated parachain merkle root - calculated: %s, mmr: %s", merkleProofData.Root.String(), mmrProof.Leaf.ParachainHeads.Hex()))
 			log.WithError(err).Error("Failed to create parachain merkle root")
 			return nil, err
 		}
@@ -249,122 +266,377 @@ func (li *BeefyListener) parablocksWithProofs(
 	return blocksWithProof, nil
 }
 
+// A commitment in the digest of a parachain block, with the range of nonces of its messages
+type blockCommitment struct {
+	digestItem parachain.AuxiliaryDigestItem
+	data       types.StorageDataRaw
+	firstNonce uint64
+	lastNonce  uint64
+}
+
+type blockCommitments struct {
+	blockNumber uint64
+	commitments []blockCommitment
+}
+
+// Finds a relay chain block at or before the given relay chain block at which the given parachain
+// block was the para head. The inclusion index is consulted first, falling back to searching backwards
+// from the given relay chain block.
+func (li *BeefyListener) findInclusionBlock(paraBlockNumber uint64, relayChainBlockNumber uint64) (uint64, error) {
+	inclusionBlockNumber, ok, err := li.inclusionIndex.Lookup(paraBlockNumber)
+	if err != nil {
+		log.WithError(err).Error("Failed to read inclusion index")
+		return 0, err
+	}
+	if ok && inclusionBlockNumber <= relayChainBlockNumber {
+		return inclusionBlockNumber, nil
+	}
+
+	log.WithFields(log.Fields{
+		"paraBlockNumber":       paraBlockNumber,
+		"relayChainBlockNumber": relayChainBlockNumber,
+	}).Info("Parachain block not in inclusion index, searching relay chain")
+
+	for {
+		headNumber, err := li.inclusionIndex.IndexRelayBlock(relayChainBlockNumber)
+		if err != nil {
+			return 0, err
+		}
+
+		if headNumber == paraBlockNumber {
+			return relayChainBlockNumber, nil
+		}
+
+		if headNumber < paraBlockNumber || relayChainBlockNumber == 0 {
+			return 0, fmt.Errorf("parachain block %d was not included in relay chain", paraBlockNumber)
+		}
+
+		relayChainBlockNumber--
+	}
+}
+
 // Searches for all lost commitments on each channel from the given parachain block number backwards
-// until it finds the given basic and incentivized nonce
+// until it finds the given nonce for every channel. Blocks are fetched concurrently in batches,
+// and blocks already covered by the commitment index are skipped unless they contain commitments.
 func (li *BeefyListener) searchForLostCommitments(
 	lastParaBlockNumber uint64,
-	basicNonceToFind uint64,
-	incentivizedNonceToFind uint64) ([]ParaBlockWithDigest, error) {
+	noncesToFind map[parachain.ChannelID]uint64) ([]ParaBlockWithDigest, error) {
 	log.WithFields(log.Fields{
-		"basicNonce":        basicNonceToFind,
-		"incentivizedNonce": incentivizedNonceToFind,
+		"nonces":            noncesToFind,
 		"latestblockNumber": lastParaBlockNumber,
 	}).Debug("Searching backwards from latest block on parachain to find block with nonce")
 
-	currentBlockNumber := lastParaBlockNumber + 1
-	basicNonceFound := false
-	incentivizedNonceFound := false
+	cursor, err := li.newSearchCursor(lastParaBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	noncesFound := make(map[parachain.ChannelID]bool, len(noncesToFind))
+	allNoncesFound := func() bool {
+		for id := range noncesToFind {
+			if !noncesFound[id] {
+				return false
+			}
+		}
+		return true
+	}
+
+	visited := false
+	lowestVisitedBlock := lastParaBlockNumber
 	var blocks []ParaBlockWithDigest
-	for (!basicNonceFound || !incentivizedNonceFound) && currentBlockNumber != 0 {
-		currentBlockNumber--
+	for !allNoncesFound() {
+		batch := cursor.nextBatch(li.catchupConcurrency())
+		if len(batch) == 0 {
+			break
+		}
+
 		log.WithFields(log.Fields{
-			"blockNumber": currentBlockNumber,
-		}).Debug("Checking header...")
+			"fromBlock": batch[len(batch)-1],
+			"toBlock":   batch[0],
+			"count":     len(batch),
+		}).Debug("Checking headers...")
 
-		blockHash, err := li.parachainConnection.API().RPC.Chain.GetBlockHash(currentBlockNumber)
+		fetched, err := li.fetchBlocksCommitments(batch)
 		if err != nil {
-			log.WithFields(log.Fields{
-				"blockNumber": currentBlockNumber,
-			}).WithError(err).Error("Failed to fetch blockhash")
 			return nil, err
 		}
 
-		header, err := li.parachainConnection.API().RPC.Chain.GetHeader(blockHash)
+		for _, block := range fetched {
+			visited = true
+			lowestVisitedBlock = block.blockNumber
+
+			var digestItemsWithData []DigestItemWithData
+			for _, commitment := range block.commitments {
+				channelID := commitment.digestItem.AsCommitment.ChannelID
+				nonceToFind, ok := noncesToFind[channelID]
+				if !ok || noncesFound[channelID] {
+					continue
+				}
+				if commitment.firstNonce <= nonceToFind {
+					noncesFound[channelID] = true
+				} else {
+					item := DigestItemWithData{commitment.digestItem, commitment.data}
+					digestItemsWithData = append(digestItemsWithData, item)
+				}
+			}
+
+			if len(digestItemsWithData) != 0 {
+				blocks = append(blocks, ParaBlockWithDigest{
+					BlockNumber:         block.blockNumber,
+					DigestItemsWithData: digestItemsWithData,
+				})
+			}
+
+			if allNoncesFound() {
+				break
+			}
+		}
+	}
+
+	if cursor.done && !allNoncesFound() {
+		// Searched all the way back to genesis
+		lowestVisitedBlock = 0
+	}
+
+	if visited {
+		// Every block between the lowest visited block and the latest block has now been indexed
+		err = li.index.SetIndexedRange(lowestVisitedBlock, lastParaBlockNumber)
+		if err != nil {
+			log.WithError(err).Error("Failed to update commitment index")
+			return nil, err
+		}
+		li.pruneCommitmentCache(lowestVisitedBlock)
+	}
+
+	return blocks, nil
+}
+
+func (li *BeefyListener) catchupConcurrency() int {
+	if li.config.CatchupConcurrency == 0 {
+		return defaultCatchupConcurrency
+	}
+	return int(li.config.CatchupConcurrency)
+}
+
+// Fetches the commitments for each of the given blocks using a bounded number of workers.
+// Results are returned in the same order as the given block numbers.
+func (li *BeefyListener) fetchBlocksCommitments(blockNumbers []uint64) ([]*blockCommitments, error) {
+	results := make([]*blockCommitments, len(blockNumbers))
+	workers := make(chan struct{}, li.catchupConcurrency())
+
+	var eg errgroup.Group
+	for i, blockNumber := range blockNumbers {
+		i, blockNumber := i, blockNumber
+		workers <- struct{}{}
+		eg.Go(func() error {
+			defer func() { <-workers }()
+			block, err := li.fetchBlockCommitments(blockNumber)
+			if err != nil {
+				return err
+			}
+			results[i] = block
+			return nil
+		})
+	}
+
+	err := eg.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Fetches the commitments in the digest of the given block, along with the messages they commit to.
+// Commitments are recorded in the commitment index, and cached for subsequent searches.
+func (li *BeefyListener) fetchBlockCommitments(blockNumber uint64) (*blockCommitments, error) {
+	li.commitmentCacheLock.Lock()
+	cached, ok := li.commitmentCache[blockNumber]
+	li.commitmentCacheLock.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	blockHash, err := li.parachainConnection.API().RPC.Chain.GetBlockHash(blockNumber)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"blockNumber": blockNumber,
+		}).WithError(err).Error("Failed to fetch blockhash")
+		return nil, err
+	}
+
+	header, err := li.parachainConnection.API().RPC.Chain.GetHeader(blockHash)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch header")
+		return nil, err
+	}
+
+	digestItems, err := parachain.ExtractAuxiliaryDigestItems(header.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	block := blockCommitments{
+		blockNumber: blockNumber,
+	}
+
+	for _, digestItem := range digestItems {
+		if !digestItem.IsCommitment {
+			continue
+		}
+
+		channel, ok := li.channels[digestItem.AsCommitment.ChannelID]
+		if !ok {
+			log.WithFields(log.Fields{
+				"blockNumber": blockNumber,
+				"channelID":   digestItem.AsCommitment.ChannelID,
+			}).Warn("Skipping commitment for unsupported channel")
+			continue
+		}
+
+		data, err := li.parachainConnection.GetDataForDigestItem(&digestItem)
 		if err != nil {
-			log.WithError(err).Error("Failed to fetch header")
 			return nil, err
 		}
 
-		digestItems, err := parachain.ExtractAuxiliaryDigestItems(header.Digest)
+		nonces, err := channel.DecodeNonces(data)
 		if err != nil {
+			log.WithError(err).Error("Failed to decode commitment messages")
 			return nil, err
 		}
 
-		var digestItemsWithData []DigestItemWithData
-
-		for _, digestItem := range digestItems {
-			if digestItem.IsCommitment {
-				channelID := digestItem.AsCommitment.ChannelID
-				if channelID.IsBasic && !basicNonceFound {
-					isRelayed, messageData, err := li.checkBasicMessageNonces(&digestItem, basicNonceToFind)
-					if err != nil {
-						return nil, err
-					}
-					if isRelayed {
-						basicNonceFound = true
-					} else {
-						item := DigestItemWithData{digestItem, messageData}
-						digestItemsWithData = append(digestItemsWithData, item)
-					}
-				}
-				if channelID.IsIncentivized && !incentivizedNonceFound {
-					isRelayed, messageData, err := li.checkIncentivizedMessageNonces(&digestItem, incentivizedNonceToFind)
-					if err != nil {
-						return nil, err
-					}
-					if isRelayed {
-						incentivizedNonceFound = true
-					} else {
-						item := DigestItemWithData{digestItem, messageData}
-						digestItemsWithData = append(digestItemsWithData, item)
-					}
-				}
-			}
+		if len(nonces) == 0 {
+			continue
 		}
 
-		if len(digestItemsWithData) != 0 {
-			block := ParaBlockWithDigest{
-				BlockNumber:         currentBlockNumber,
-				DigestItemsWithData: digestItemsWithData,
+		commitment := blockCommitment{
+			digestItem: digestItem,
+			data:       data,
+			firstNonce: nonces[0],
+			lastNonce:  nonces[0],
+		}
+		for _, nonce := range nonces {
+			if nonce < commitment.firstNonce {
+				commitment.firstNonce = nonce
+			}
+			if nonce > commitment.lastNonce {
+				commitment.lastNonce = nonce
 			}
-			blocks = append(blocks, block)
+		}
+
+		err = li.index.PutCommitmentRange(store.CommitmentRange{
+			ParaBlock:  blockNumber,
+			Channel:    channel.Name(),
+			FirstNonce: commitment.firstNonce,
+			LastNonce:  commitment.lastNonce,
+		})
+		if err != nil {
+			log.WithError(err).Error("Failed to record commitment in index")
+			return nil, err
+		}
+
+		block.commitments = append(block.commitments, commitment)
+	}
+
+	if len(block.commitments) > 0 {
+		li.commitmentCacheLock.Lock()
+		li.commitmentCache[blockNumber] = &block
+		li.commitmentCacheLock.Unlock()
+	}
+
+	return &block, nil
+}
+
+// Removes cached commitments for blocks below the given block. Their messages have already
+// been delivered, so future searches will not visit them.
+func (li *BeefyListener) pruneCommitmentCache(belowBlock uint64) {
+	li.commitmentCacheLock.Lock()
+	defer li.commitmentCacheLock.Unlock()
+
+	for blockNumber := range li.commitmentCache {
+		if blockNumber < belowBlock {
+			delete(li.commitmentCache, blockNumber)
 		}
 	}
+}
 
-	return blocks, nil
+// searchCursor yields the parachain blocks to visit when searching backwards for commitments.
+// Blocks within the indexed range are skipped unless they are known to contain commitments.
+type searchCursor struct {
+	next          uint64
+	done          bool
+	indexedRange  *store.IndexedRange
+	indexedBlocks []uint64
 }
 
-func (li *BeefyListener) checkBasicMessageNonces(
-	digestItem *parachain.AuxiliaryDigestItem,
-	nonceToFind uint64,
-) (bool, types.StorageDataRaw, error) {
-	messages, data, err := li.parachainConnection.GetBasicOutboundMessages(*digestItem)
+func (li *BeefyListener) newSearchCursor(lastParaBlockNumber uint64) (*searchCursor, error) {
+	indexedRange, err := li.index.GetIndexedRange()
 	if err != nil {
-		return false, nil, err
+		log.WithError(err).Error("Failed to read commitment index")
+		return nil, err
 	}
 
-	for _, message := range messages {
-		if message.Nonce <= nonceToFind {
-			return true, data, nil
-		}
+	cursor := searchCursor{
+		next:         lastParaBlockNumber,
+		indexedRange: indexedRange,
+	}
+
+	if indexedRange == nil || indexedRange.FromBlock > lastParaBlockNumber {
+		cursor.indexedRange = nil
+		return &cursor, nil
 	}
-	return false, data, nil
-}
 
-func (li *BeefyListener) checkIncentivizedMessageNonces(
-	digestItem *parachain.AuxiliaryDigestItem,
-	nonceToFind uint64,
-) (bool, types.StorageDataRaw, error) {
+	toBlock := indexedRange.ToBlock
+	if toBlock > lastParaBlockNumber {
+		toBlock = lastParaBlockNumber
+	}
 
-	messages, data, err := li.parachainConnection.GetIncentivizedOutboundMessages(*digestItem)
+	ranges, err := li.index.GetCommitmentRanges(indexedRange.FromBlock, toBlock)
 	if err != nil {
-		return false, nil, err
+		log.WithError(err).Error("Failed to read commitment index")
+		return nil, err
 	}
 
-	for _, message := range messages {
-		if message.Nonce <= nonceToFind {
-			return true, data, nil
+	for _, commitmentRange := range ranges {
+		count := len(cursor.indexedBlocks)
+		if count == 0 || cursor.indexedBlocks[count-1] != commitmentRange.ParaBlock {
+			cursor.indexedBlocks = append(cursor.indexedBlocks, commitmentRange.ParaBlock)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"fromBlock":   indexedRange.FromBlock,
+		"toBlock":     toBlock,
+		"commitments": len(cursor.indexedBlocks),
+	}).Debug("Using commitment index to skip scanned blocks")
+
+	return &cursor, nil
+}
+
+// Returns up to size block numbers in descending order
+func (c *searchCursor) nextBatch(size int) []uint64 {
+	var batch []uint64
+	for len(batch) < size && !c.done {
+		if c.indexedRange != nil && c.next >= c.indexedRange.FromBlock && c.next <= c.indexedRange.ToBlock {
+			if len(c.indexedBlocks) > 0 {
+				batch = append(batch, c.indexedBlocks[0])
+				c.indexedBlocks = c.indexedBlocks[1:]
+				continue
+			}
+			if c.indexedRange.FromBlock == 0 {
+				c.done = true
+				break
+			}
+			c.next = c.indexedRange.FromBlock - 1
+			continue
+		}
+
+		batch = append(batch, c.next)
+		if c.next == 0 {
+			c.done = true
+		} else {
+			c.next--
 		}
 	}
-	return false, data, nil
+	return batch
 }
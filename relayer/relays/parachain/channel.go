@@ -0,0 +1,178 @@
+package parachain
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+// Channel delivers the messages committed to by a parachain outbound channel to
+// the matching inbound channel on Ethereum
+type Channel interface {
+	ID() parachain.ChannelID
+	// Name identifies the channel in logs and in the commitment index
+	Name() string
+	// OutboundModule is the name of the pallet implementing the outbound channel on the parachain
+	OutboundModule() string
+	// InboundNonce returns the nonce of the last message accepted by the inbound channel on Ethereum
+	InboundNonce(opts *bind.CallOpts) (uint64, error)
+	// DecodeNonces returns the nonces of the messages in the given SCALE-encoded commitment data
+	DecodeNonces(data []byte) ([]uint64, error)
+	// DecodeTargets returns the addresses of the applications on Ethereum to which the messages
+	// in the commitment data are sent
+	DecodeTargets(data []byte) ([]common.Address, error)
+	// LogMessages returns a representation of the messages in the commitment data suitable for logging
+	LogMessages(data []byte) (interface{}, error)
+	// Submit simulates and then sends the transaction delivering the messages to the inbound channel.
+	// A *ethereum.RevertError is returned if the simulated transaction reverts.
+	Submit(opts *bind.TransactOpts, data []byte, proof *ParachainProof) (*types.Transaction, error)
+	// FilterMessageDispatched returns the MessageDispatched events emitted by the inbound channel
+	FilterMessageDispatched(opts *bind.FilterOpts) ([]MessageDispatched, error)
+}
+
+// FeeChannel is implemented by channels whose messages pay a fee to the relayer delivering them
+type FeeChannel interface {
+	Channel
+	// TotalFee returns the sum of the fees paid by the messages in the commitment data
+	TotalFee(data []byte) (*big.Int, error)
+	// EstimateSubmitGas estimates the gas used to deliver the messages in the commitment data.
+	// A *ethereum.RevertError is returned if the transaction reverts.
+	EstimateSubmitGas(opts *bind.TransactOpts, data []byte, proof *ParachainProof) (uint64, error)
+}
+
+// MessageDispatched is emitted by an inbound channel on Ethereum for each message it dispatches
+type MessageDispatched struct {
+	Nonce       uint64
+	Result      bool
+	TxHash      common.Hash
+	BlockNumber uint64
+}
+
+type channelFactory func(conn *ethereum.Connection, address common.Address) (Channel, error)
+
+type registeredChannel struct {
+	name    string
+	factory channelFactory
+}
+
+var channelRegistry = map[parachain.ChannelID]registeredChannel{}
+
+// registerChannel makes a channel implementation available to the relay. The name is the key
+// under which the address of the channel's inbound contract is configured.
+func registerChannel(id parachain.ChannelID, name string, factory channelFactory) {
+	channelRegistry[id] = registeredChannel{name: name, factory: factory}
+}
+
+// Channels holds the channels supported by the relay, keyed by ID
+type Channels map[parachain.ChannelID]Channel
+
+// NewChannels creates the registered channels using the given inbound channel addresses, keyed
+// by channel name. Every registered channel must have an address.
+func NewChannels(conn *ethereum.Connection, addresses map[string]string) (Channels, error) {
+	channels := make(Channels)
+	for id, registered := range channelRegistry {
+		address, ok := addresses[registered.name]
+		if !ok || !common.IsHexAddress(address) {
+			return nil, retry.Fatal(fmt.Errorf("no inbound channel address configured for channel '%s'", registered.name))
+		}
+
+		channel, err := registered.factory(conn, common.HexToAddress(address))
+		if err != nil {
+			return nil, err
+		}
+		channels[id] = channel
+	}
+	return channels, nil
+}
+
+// IDs returns the IDs of the channels in ascending order
+func (c Channels) IDs() []parachain.ChannelID {
+	ids := make([]parachain.ChannelID, 0, len(c))
+	for id := range c {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// ParachainProof proves that a commitment is included in a parachain header, and that
+// the header is included in the MMR held by the light client. Channels convert it into
+// the types generated for their contract.
+type ParachainProof struct {
+	ParaVerifyInput     ParaVerifyInput
+	BeefyMMRLeafPartial BeefyMMRLeafPartial
+	SimplifiedMMRProof  SimplifiedMMRProof
+}
+
+type ParaVerifyInput struct {
+	OwnParachainHeadPrefixBytes []byte
+	OwnParachainHeadSuffixBytes []byte
+	ParachainHeadProof          ParaHeadProof
+}
+
+type ParaHeadProof struct {
+	Pos   *big.Int
+	Width *big.Int
+	Proof [][32]byte
+}
+
+type BeefyMMRLeafPartial struct {
+	Version              uint8
+	ParentNumber         uint32
+	ParentHash           [32]byte
+	NextAuthoritySetId   uint64 // revive:disable-line
+	NextAuthoritySetLen  uint32
+	NextAuthoritySetRoot [32]byte
+}
+
+type SimplifiedMMRProof struct {
+	MerkleProofItems         [][32]byte
+	MerkleProofOrderBitField uint64
+}
+
+// Builds the proof for the commitment in the message package
+func NewParachainProof(msgPackage *MessagePackage) (*ParachainProof, error) {
+	prefix, suffix, err := splitOwnParachainHead(msgPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	var merkleProofItems [][32]byte
+	for _, proofItem := range msgPackage.simplifiedMMRProof.MerkleProofItems {
+		merkleProofItems = append(merkleProofItems, proofItem)
+	}
+
+	leaf := msgPackage.simplifiedMMRProof.Leaf
+
+	return &ParachainProof{
+		ParaVerifyInput: ParaVerifyInput{
+			OwnParachainHeadPrefixBytes: prefix,
+			OwnParachainHeadSuffixBytes: suffix,
+			ParachainHeadProof: ParaHeadProof{
+				Pos:   big.NewInt(int64(msgPackage.merkleProofData.ProvenLeafIndex)),
+				Width: big.NewInt(int64(msgPackage.merkleProofData.NumberOfLeaves)),
+				Proof: msgPackage.merkleProofData.Proof,
+			},
+		},
+		BeefyMMRLeafPartial: BeefyMMRLeafPartial{
+			Version:              uint8(leaf.Version),
+			ParentNumber:         uint32(leaf.ParentNumberAndHash.ParentNumber),
+			ParentHash:           leaf.ParentNumberAndHash.Hash,
+			NextAuthoritySetId:   uint64(leaf.BeefyNextAuthoritySet.ID),
+			NextAuthoritySetLen:  uint32(leaf.BeefyNextAuthoritySet.Len),
+			NextAuthoritySetRoot: leaf.BeefyNextAuthoritySet.Root,
+		},
+		SimplifiedMMRProof: SimplifiedMMRProof{
+			MerkleProofItems:         merkleProofItems,
+			MerkleProofOrderBitField: msgPackage.simplifiedMMRProof.MerkleProofOrder,
+		},
+	}, nil
+}
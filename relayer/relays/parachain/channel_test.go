@@ -0,0 +1,30 @@
+package parachain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+func TestNewChannelsResolvesAddressesByName(t *testing.T) {
+	conn := ethereum.NewConnection("", nil)
+
+	channels, err := NewChannels(conn, map[string]string{
+		"basic":        "0xEDa338E4dC46038493b885327842fD3E301CaB39",
+		"incentivized": "0xFc97A6197dc90bef6bbEFD672742Ed75E9768553",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []parachain.ChannelID{basicChannelID, incentivizedChannelID}, channels.IDs())
+	assert.Equal(t, "basic", channels[basicChannelID].Name())
+	assert.Equal(t, "incentivized", channels[incentivizedChannelID].Name())
+
+	_, err = NewChannels(conn, map[string]string{
+		"basic": "0xEDa338E4dC46038493b885327842fD3E301CaB39",
+	})
+	assert.True(t, retry.IsFatal(err))
+	assert.EqualError(t, err, "no inbound channel address configured for channel 'incentivized'")
+}
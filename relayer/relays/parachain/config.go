@@ -1,10 +1,21 @@
 package parachain
 
-import "github.com/snowfork/snowbridge/relayer/config"
+import (
+	"github.com/snowfork/snowbridge/relayer/config"
+	"github.com/snowfork/snowbridge/relayer/relays/filter"
+)
 
 type Config struct {
-	Source SourceConfig `mapstructure:"source"`
-	Sink   SinkConfig   `mapstructure:"sink"`
+	Source   SourceConfig   `mapstructure:"source"`
+	Sink     SinkConfig     `mapstructure:"sink"`
+	Database DatabaseConfig `mapstructure:"database"`
+	Filter   filter.Config  `mapstructure:"filter"`
+}
+
+type DatabaseConfig struct {
+	// Path to the sqlite database holding the commitment and inclusion indexes.
+	// If empty, the indexes are kept in memory and rebuilt after a restart.
+	Path string `mapstructure:"path"`
 }
 
 type SourceConfig struct {
@@ -12,20 +23,46 @@ type SourceConfig struct {
 	Parachain config.ParachainConfig `mapstructure:"parachain"`
 	Ethereum  config.EthereumConfig  `mapstructure:"ethereum"`
 	Contracts SourceContractsConfig   `mapstructure:"contracts"`
+	// Number of parachain blocks to fetch concurrently when searching for lost commitments
+	CatchupConcurrency uint64 `mapstructure:"catchup-concurrency"`
 }
 
 type SourceContractsConfig struct {
 	BeefyLightClient string `mapstructure:"BeefyLightClient"`
-	BasicInboundChannel        string `mapstructure:"BasicInboundChannel"`
-	IncentivizedInboundChannel string `mapstructure:"IncentivizedInboundChannel"`
+	// Address of the inbound channel on Ethereum for each channel, keyed by channel name
+	InboundChannels map[string]string `mapstructure:"InboundChannels"`
 }
 
 type SinkConfig struct {
-	Ethereum  config.EthereumConfig `mapstructure:"ethereum"`
-	Contracts SinkContractsConfig   `mapstructure:"contracts"`
+	Ethereum      config.EthereumConfig `mapstructure:"ethereum"`
+	Contracts     SinkContractsConfig   `mapstructure:"contracts"`
+	Profitability ProfitabilityConfig   `mapstructure:"profitability"`
+	// Number of descendants an Ethereum block needs before the delivery tracker records its
+	// MessageDispatched events, so that reorged events aren't recorded
+	DescendantsUntilFinal uint64 `mapstructure:"descendants-until-final"`
+	// Number of times delivery of a message package may fail before the package is dead-lettered.
+	// Defaults to 5.
+	MaxPackageAttempts uint64 `mapstructure:"max-package-attempts"`
+}
+
+type ProfitabilityConfig struct {
+	// Defer incentivized channel commitments whose fees do not cover the cost of delivering them
+	Enabled bool `mapstructure:"enabled"`
+	// Required ratio of fees to delivery cost. Defaults to 1.
+	Margin float64 `mapstructure:"margin"`
+	// Price of 1 ETH in DOT. Not needed while fees are paid in ether, as they are by the runtimes,
+	// in which case the fees are compared with the delivery cost in wei.
+	EthDotPrice string `mapstructure:"eth-dot-price"`
+	// File holding the price of 1 ETH in DOT, written by an external price feed. Overrides EthDotPrice.
+	PriceFile string `mapstructure:"price-file"`
+	// Decimals of the currency in which message fees are paid. Defaults to 10 if a price is
+	// configured, and to 18 otherwise.
+	FeeDecimals *uint8 `mapstructure:"fee-decimals"`
+	// Seconds between checks of deferred commitments
+	RecheckInterval uint64 `mapstructure:"recheck-interval"`
 }
 
 type SinkContractsConfig struct {
-	BasicInboundChannel        string `mapstructure:"BasicInboundChannel"`
-	IncentivizedInboundChannel string `mapstructure:"IncentivizedInboundChannel"`
+	// Address of the inbound channel on Ethereum for each channel, keyed by channel name
+	InboundChannels map[string]string `mapstructure:"InboundChannels"`
 }
@@ -0,0 +1,87 @@
+package parachain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/snowfork/snowbridge/relayer/retry"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Number of times delivery of a message package may fail before the package is dead-lettered
+const defaultMaxPackageAttempts = 5
+
+// Writes the message package unless its channel is paused. Errors which are neither transient nor
+// fatal, including simulation reverts, are counted against the package instead of stopping the relay,
+// and the package is tried again when the beefy listener next finds it undelivered. Once its attempts
+// are used up, the package is dead-lettered. Nonces are sequential, so no later package on the channel
+// can be delivered either, and the channel is paused until the package is replayed or purged from
+// the command line.
+func (wr *EthereumChannelWriter) writeMessagePackage(options *bind.TransactOpts, msg *MessagePackage) error {
+	channel, ok := wr.channels[msg.channelID]
+	if !ok {
+		return fmt.Errorf("unsupported channel %v", msg.channelID)
+	}
+	commitmentHash := common.Hash(msg.commitmentHash)
+
+	logger := log.WithFields(log.Fields{
+		"channel":        channel.Name(),
+		"commitmentHash": commitmentHash.Hex(),
+		"paraBlock":      msg.paraHead.Number,
+	})
+
+	paused, err := wr.index.IsChannelPaused(channel.Name())
+	if err != nil {
+		return err
+	}
+	if paused {
+		pausedChannels.WithLabelValues(channel.Name()).Set(1)
+		logger.Warn("Skipping message package, channel is paused until its dead-lettered message package is replayed")
+		return nil
+	}
+	pausedChannels.WithLabelValues(channel.Name()).Set(0)
+
+	err = wr.WriteChannel(options, msg)
+	if err == nil {
+		return wr.index.ClearMessagePackageFailures(channel.Name(), commitmentHash)
+	}
+	if errors.Is(err, errMessagePackageDeferred) || options.Context.Err() != nil ||
+		retry.IsFatal(err) || retry.IsTransient(err) {
+		return err
+	}
+
+	failure, recordErr := wr.index.RecordMessagePackageFailure(
+		channel.Name(),
+		commitmentHash,
+		uint64(msg.paraHead.Number),
+		msg.commitmentData,
+		err.Error(),
+		wr.maxPackageAttempts(),
+	)
+	if recordErr != nil {
+		log.WithError(recordErr).Error("Failed to record message package failure")
+		return err
+	}
+
+	logger = logger.WithError(err).WithField("attempts", failure.Attempts)
+	if failure.DeadLettered {
+		deadLetteredMessagePackages.WithLabelValues(channel.Name()).Inc()
+		pausedChannels.WithLabelValues(channel.Name()).Set(1)
+		logger.Error("ALERT: Message package failed too many times and was dead-lettered, channel is paused until it is replayed")
+	} else {
+		logger.Warn("Failed to write message package, it will be retried when it is next found undelivered")
+	}
+
+	return nil
+}
+
+func (wr *EthereumChannelWriter) maxPackageAttempts() uint64 {
+	if wr.config.MaxPackageAttempts == 0 {
+		return defaultMaxPackageAttempts
+	}
+	return wr.config.MaxPackageAttempts
+}
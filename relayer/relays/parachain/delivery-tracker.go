@@ -0,0 +1,236 @@
+package parachain
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Number of blocks queried at a time for MessageDispatched events
+const dispatchBlockWindow = 1000
+
+// DeliveryTracker watches the MessageDispatched events emitted by the inbound channels on Ethereum,
+// and records the outcome of each message submitted by the relay
+type DeliveryTracker struct {
+	config   *SinkConfig
+	conn     *ethereum.Connection
+	index    *store.Database
+	channels Channels
+	// Transaction whose gas was last recorded, as several messages are dispatched by each transaction
+	lastGasTxHash common.Hash
+}
+
+func NewDeliveryTracker(
+	config *SinkConfig,
+	conn *ethereum.Connection,
+	index *store.Database,
+) *DeliveryTracker {
+	return &DeliveryTracker{
+		config: config,
+		conn:   conn,
+		index:  index,
+	}
+}
+
+func (dt *DeliveryTracker) Start(ctx context.Context, eg *errgroup.Group) error {
+	channels, err := NewChannels(dt.conn, dt.config.Contracts.InboundChannels)
+	if err != nil {
+		return err
+	}
+	dt.channels = channels
+
+	eg.Go(func() error {
+		err := dt.watchDispatchedMessages(ctx)
+		if err != nil {
+			log.WithError(err).Error("Failed to track message deliveries")
+		}
+		return err
+	})
+
+	return nil
+}
+
+// Track records that the given transaction delivers the messages in the commitment
+func (dt *DeliveryTracker) Track(channel Channel, data []byte, txHash common.Hash) error {
+	nonces, err := channel.DecodeNonces(data)
+	if err != nil {
+		return err
+	}
+
+	for _, nonce := range nonces {
+		err := dt.index.PutSubmittedMessage(channel.Name(), nonce, txHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Watches MessageDispatched events in blocks which have at least DescendantsUntilFinal descendants, so
+// that events which may still be reorged out aren't recorded. The last block processed is kept in the
+// index, so that events emitted while the relay was stopped are recorded when it starts again.
+func (dt *DeliveryTracker) watchDispatchedMessages(ctx context.Context) error {
+	headers := make(chan *gethTypes.Header, 5)
+
+	sub, err := dt.conn.GetClient().SubscribeNewHead(ctx, headers)
+	if err != nil {
+		log.WithError(err).Error("Error creating ethereum header subscription")
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	var lastBlockNumber *uint64
+	blockNumber, ok, err := dt.index.GetLastTrackedEthereumBlock()
+	if err != nil {
+		return err
+	}
+	if ok {
+		lastBlockNumber = &blockNumber
+	}
+
+	descendantsUntilFinal := dt.config.DescendantsUntilFinal
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			log.WithError(err).Error("Error with ethereum header subscription")
+			return err
+		case header := <-headers:
+			health.Progress("delivery-tracker")
+
+			blockNumber := header.Number.Uint64()
+			if blockNumber < descendantsUntilFinal {
+				continue
+			}
+			finalizedBlockNumber := blockNumber - descendantsUntilFinal
+
+			startBlock := finalizedBlockNumber
+			if lastBlockNumber != nil {
+				if finalizedBlockNumber <= *lastBlockNumber {
+					continue
+				}
+				startBlock = *lastBlockNumber + 1
+			}
+
+			err := dt.processFinalizedBlocks(ctx, startBlock, finalizedBlockNumber)
+			if err != nil {
+				return err
+			}
+
+			lastBlockNumber = &finalizedBlockNumber
+		}
+	}
+}
+
+// Processes the blocks in windows of dispatchBlockWindow blocks, to stay within the range limits of
+// eth_getLogs after the relay was stopped. Progress is persisted after each window.
+func (dt *DeliveryTracker) processFinalizedBlocks(ctx context.Context, startBlock, endBlock uint64) error {
+	for start := startBlock; start <= endBlock; {
+		end := endBlock
+		if end-start >= dispatchBlockWindow {
+			end = start + dispatchBlockWindow - 1
+		}
+
+		err := dt.processDispatchedMessages(ctx, start, end)
+		if err != nil {
+			return err
+		}
+
+		err = dt.index.SetLastTrackedEthereumBlock(end)
+		if err != nil {
+			return err
+		}
+
+		start = end + 1
+	}
+
+	return nil
+}
+
+func (dt *DeliveryTracker) processDispatchedMessages(ctx context.Context, startBlock uint64, endBlock uint64) error {
+	for _, channel := range dt.channels {
+		events, err := channel.FilterMessageDispatched(&bind.FilterOpts{
+			Start:   startBlock,
+			End:     &endBlock,
+			Context: ctx,
+		})
+		if err != nil {
+			log.WithError(err).Error("Failed to query MessageDispatched events")
+			return err
+		}
+
+		for _, event := range events {
+			err := dt.recordDispatchedMessage(ctx, channel, event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (dt *DeliveryTracker) recordDispatchedMessage(ctx context.Context, channel Channel, event MessageDispatched) error {
+	delivery, err := dt.index.GetMessageDelivery(channel.Name(), event.Nonce)
+	if err != nil {
+		return err
+	}
+
+	err = dt.index.PutDispatchedMessage(channel.Name(), event.Nonce, event.Result, event.TxHash, event.BlockNumber)
+	if err != nil {
+		log.WithError(err).Error("Failed to record dispatched message")
+		return err
+	}
+
+	messagesDispatched.WithLabelValues(channel.Name(), dispatchResultLabel(event.Result)).Inc()
+	channelNonce.WithLabelValues(channel.Name(), "ethereum").Set(float64(event.Nonce))
+
+	logger := log.WithFields(log.Fields{
+		"channel":     channel.Name(),
+		"nonce":       event.Nonce,
+		"txHash":      event.TxHash.Hex(),
+		"blockNumber": event.BlockNumber,
+		"submittedBy": "other",
+	})
+	if delivery != nil && delivery.SubmitTxHash != (common.Hash{}) {
+		logger = logger.WithField("submittedBy", "relay")
+		if delivery.SubmitTxHash != event.TxHash {
+			logger = logger.WithField("submitTxHash", delivery.SubmitTxHash.Hex())
+		} else {
+			dt.recordGasUsed(ctx, channel, event.TxHash)
+		}
+	}
+
+	if !event.Result {
+		logger.Error("ALERT: Message dispatch failed on Ethereum")
+		return nil
+	}
+
+	logger.Info("Message dispatched on Ethereum")
+	return nil
+}
+
+func (dt *DeliveryTracker) recordGasUsed(ctx context.Context, channel Channel, txHash common.Hash) {
+	if txHash == dt.lastGasTxHash {
+		return
+	}
+	dt.lastGasTxHash = txHash
+
+	gas, err := dt.conn.TransactionGasUsed(ctx, txHash)
+	if err != nil {
+		log.WithError(err).WithField("txHash", txHash.Hex()).Warn("Failed to get gas used by transaction")
+		return
+	}
+	gasUsed.WithLabelValues(channel.Name()).Add(float64(gas))
+}
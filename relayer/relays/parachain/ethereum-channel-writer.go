@@ -1,11 +1,12 @@
 package parachain
 
 import (
+	"bytes"
 	"context"
-	"encoding/hex"
 	"errors"
+	"fmt"
 	"math/big"
-	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -15,52 +16,87 @@ import (
 
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
 	"github.com/snowfork/snowbridge/relayer/chain/parachain"
-	"github.com/snowfork/snowbridge/relayer/contracts/basic"
-	"github.com/snowfork/snowbridge/relayer/contracts/incentivized"
+	"github.com/snowfork/snowbridge/relayer/contracts/beefylightclient"
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/relays/filter"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain/store"
+	"github.com/snowfork/snowbridge/relayer/retry"
 
 	gsrpcTypes "github.com/snowfork/go-substrate-rpc-client/v3/types"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// MessagePackageBuilder rebuilds the proofs of a message package against the latest MMR root
+// held by the light client
+type MessagePackageBuilder interface {
+	RebuildMessagePackage(ctx context.Context, msg *MessagePackage) (*MessagePackage, error)
+}
+
 type EthereumChannelWriter struct {
-	config                     *SinkConfig
-	conn                       *ethereum.Connection
-	basicInboundChannel        *basic.BasicInboundChannel
-	incentivizedInboundChannel *incentivized.IncentivizedInboundChannel
-	messagePackages            <-chan MessagePackage
+	config           *SinkConfig
+	conn             *ethereum.Connection
+	lightClientAddr  common.Address
+	beefyLightClient *beefylightclient.Contract
+	channels         Channels
+	messagePackages  <-chan MessagePackage
+	builder          MessagePackageBuilder
+	tracker          *DeliveryTracker
+	profitability    *ProfitabilityPolicy
+	filter           *filter.Filter
+	index            *store.Database
+	// Message packages deferred by the profitability policy, in nonce order
+	deferred map[parachain.ChannelID][]*MessagePackage
 }
 
+// NewEthereumChannelWriter creates the writer. The address of the light client is taken from the
+// source contracts, as the beefy listener reads the same contract.
 func NewEthereumChannelWriter(
 	config *SinkConfig,
 	conn *ethereum.Connection,
+	lightClientAddr common.Address,
 	messagePackages <-chan MessagePackage,
+	builder MessagePackageBuilder,
+	tracker *DeliveryTracker,
+	filter *filter.Filter,
+	index *store.Database,
 ) (*EthereumChannelWriter, error) {
+	var profitability *ProfitabilityPolicy
+	if config.Profitability.Enabled {
+		policy, err := NewProfitabilityPolicy(&config.Profitability)
+		if err != nil {
+			return nil, err
+		}
+		profitability = policy
+	}
+
 	return &EthereumChannelWriter{
-		config:                     config,
-		conn:                       conn,
-		basicInboundChannel:        nil,
-		incentivizedInboundChannel: nil,
-		messagePackages:            messagePackages,
+		config:          config,
+		conn:            conn,
+		lightClientAddr: lightClientAddr,
+		channels:        nil,
+		messagePackages: messagePackages,
+		builder:         builder,
+		tracker:         tracker,
+		profitability:   profitability,
+		filter:          filter,
+		index:           index,
+		deferred:        make(map[parachain.ChannelID][]*MessagePackage),
 	}, nil
 }
 
 func (wr *EthereumChannelWriter) Start(ctx context.Context, eg *errgroup.Group) error {
-	var address common.Address
-
-	address = common.HexToAddress(wr.config.Contracts.BasicInboundChannel)
-	basic, err := basic.NewBasicInboundChannel(address, wr.conn.GetClient())
+	beefyLightClient, err := beefylightclient.NewContract(wr.lightClientAddr, wr.conn.GetClient())
 	if err != nil {
 		return err
 	}
-	wr.basicInboundChannel = basic
+	wr.beefyLightClient = beefyLightClient
 
-	address = common.HexToAddress(wr.config.Contracts.IncentivizedInboundChannel)
-	incentivized, err := incentivized.NewIncentivizedInboundChannel(address, wr.conn.GetClient())
+	channels, err := NewChannels(wr.conn, wr.config.Contracts.InboundChannels)
 	if err != nil {
 		return err
 	}
-	wr.incentivizedInboundChannel = incentivized
+	wr.channels = channels
 
 	eg.Go(func() error {
 		return wr.writeMessagesLoop(ctx)
@@ -71,12 +107,12 @@ func (wr *EthereumChannelWriter) Start(ctx context.Context, eg *errgroup.Group)
 
 func (wr *EthereumChannelWriter) makeTxOpts(ctx context.Context) *bind.TransactOpts {
 	chainID := wr.conn.ChainID()
-	keypair := wr.conn.GetKP()
+	signer := wr.conn.Signer()
 
 	options := bind.TransactOpts{
-		From: keypair.CommonAddress(),
+		From: signer.CommonAddress(),
 		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
-			return types.SignTx(tx, types.NewLondonSigner(chainID), keypair.PrivateKey())
+			return signer.SignTx(ctx, tx, chainID)
 		},
 		Context: ctx,
 	}
@@ -102,6 +138,14 @@ func (wr *EthereumChannelWriter) makeTxOpts(ctx context.Context) *bind.TransactO
 
 func (wr *EthereumChannelWriter) writeMessagesLoop(ctx context.Context) error {
 	options := wr.makeTxOpts(ctx)
+
+	recheckInterval := wr.config.Profitability.RecheckInterval
+	if recheckInterval == 0 {
+		recheckInterval = defaultRecheckInterval
+	}
+	ticker := time.NewTicker(time.Duration(recheckInterval) * time.Second)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -111,238 +155,457 @@ func (wr *EthereumChannelWriter) writeMessagesLoop(ctx context.Context) error {
 				<-wr.messagePackages
 			}
 			return nil
+		case <-ticker.C:
+			err := wr.writeDeferred(options)
+			if err != nil {
+				log.WithError(err).Error("Error submitting deferred message to ethereum")
+				return err
+			}
 		case messagePackage := <-wr.messagePackages:
-			err := wr.WriteChannel(options, &messagePackage)
+			err := wr.writeOrDefer(options, &messagePackage)
 			if err != nil {
 				log.WithError(err).Error("Error submitting message to ethereum")
 				return err
 			}
+			health.Progress("ethereum-writer")
+		}
+	}
+}
+
+// Writes the message package, or queues it if it was deferred by the profitability policy. Packages
+// arriving while earlier packages for the same channel are deferred are queued behind them, since
+// the inbound channel only accepts messages in nonce order.
+func (wr *EthereumChannelWriter) writeOrDefer(options *bind.TransactOpts, msg *MessagePackage) error {
+	if len(wr.deferred[msg.channelID]) > 0 {
+		wr.deferMessagePackage(msg)
+		return nil
+	}
+
+	err := wr.writeMessagePackage(options, msg)
+	if errors.Is(err, errMessagePackageDeferred) {
+		wr.deferMessagePackage(msg)
+		return nil
+	}
+	return err
+}
+
+// Queues the message package behind the packages already deferred for its channel. The beefy listener
+// emits every undelivered package again on each new MMR root, so a package which is already queued
+// replaces the queued copy, whose proofs are older.
+func (wr *EthereumChannelWriter) deferMessagePackage(msg *MessagePackage) {
+	queue := wr.deferred[msg.channelID]
+	for i, queued := range queue {
+		if queued.commitmentHash == msg.commitmentHash {
+			queue[i] = msg
+			return
+		}
+	}
+
+	wr.deferred[msg.channelID] = append(queue, msg)
+	wr.updateDeferredMetric(msg.channelID)
+}
+
+// Retries deferred message packages in order, until a package is deferred again
+func (wr *EthereumChannelWriter) writeDeferred(options *bind.TransactOpts) error {
+	for channelID, queue := range wr.deferred {
+		for len(queue) > 0 {
+			err := wr.writeMessagePackage(options, queue[0])
+			if errors.Is(err, errMessagePackageDeferred) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			queue = queue[1:]
+		}
+
+		if len(queue) == 0 {
+			delete(wr.deferred, channelID)
+		} else {
+			wr.deferred[channelID] = queue
 		}
+		wr.updateDeferredMetric(channelID)
+	}
+
+	return nil
+}
+
+func (wr *EthereumChannelWriter) updateDeferredMetric(channelID parachain.ChannelID) {
+	channel, ok := wr.channels[channelID]
+	if !ok {
+		return
 	}
+	deferredMessagePackages.WithLabelValues(channel.Name()).Set(float64(len(wr.deferred[channelID])))
 }
 
-// Submit sends a SCALE-encoded message to an application deployed on the Ethereum network
-func (wr *EthereumChannelWriter) WriteBasicChannel(
+// Splits the head data of our parachain around the commitment hash, and checks that the
+// parts match the pre-leaf proven by the parachain heads merkle proof
+func splitOwnParachainHead(msgPackage *MessagePackage) ([]byte, []byte, error) {
+	prefix, suffix, err := parachain.SplitParaHead(msgPackage.paraHead, msgPackage.channelID, msgPackage.commitmentHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encodedParaID, err := gsrpcTypes.EncodeToBytes(msgPackage.paraId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var preLeaf []byte
+	preLeaf = append(preLeaf, encodedParaID...)
+	preLeaf = append(preLeaf, prefix...)
+	preLeaf = append(preLeaf, msgPackage.commitmentHash[:]...)
+	preLeaf = append(preLeaf, suffix...)
+	if !bytes.Equal(preLeaf, msgPackage.merkleProofData.ProvenPreLeaf) {
+		return nil, nil, retry.Fatal(errors.New("encoded parachain header does not match proven parachain head"))
+	}
+
+	return prefix, suffix, nil
+}
+
+// WriteChannel submits the message package to its channel on Ethereum. Packages built for an MMR root
+// other than the light client's latest root are rebuilt first. The transaction is only sent
+// if it succeeds when simulated. Otherwise, depending on the revert reason, proofs are regenerated
+// against the light client's latest MMR root, the package is skipped, or the revert is returned
+// so that it is counted against the package. If the profitability
+// policy is enabled, packages whose fees don't cover the cost of delivery are deferred. Packages
+// in which every message is rejected by the filter are skipped.
+func (wr *EthereumChannelWriter) WriteChannel(
 	options *bind.TransactOpts,
-	msgPackage *MessagePackage,
-	msgs []parachain.BasicOutboundChannelMessage,
+	msg *MessagePackage,
 ) error {
-	var messages []basic.BasicInboundChannelMessage
-	for _, m := range msgs {
-		messages = append(messages,
-			basic.BasicInboundChannelMessage{
-				Target:  m.Target,
-				Nonce:   m.Nonce,
-				Payload: m.Payload,
-			},
-		)
-	}
-
-	paraHeadProof := basic.ParachainLightClientParachainHeadProof{
-		Pos:   big.NewInt(int64(msgPackage.merkleProofData.ProvenLeafIndex)),
-		Width: big.NewInt(int64(msgPackage.merkleProofData.NumberOfLeaves)),
-		Proof: msgPackage.merkleProofData.Proof,
-	}
-
-	ownParachainHeadBytes := msgPackage.merkleProofData.ProvenPreLeaf
-	ownParachainHeadBytesString := hex.EncodeToString(ownParachainHeadBytes)
-	commitmentHashString := hex.EncodeToString(msgPackage.commitmentHash[:])
-	prefixSuffix := strings.Split(ownParachainHeadBytesString, commitmentHashString)
-	if len(prefixSuffix) != 2 {
-		return errors.New("error splitting parachain header into prefix and suffix")
-	}
-	paraIDHex, err := gsrpcTypes.EncodeToHexString(msgPackage.paraId)
+	relay, err := wr.filterMessagePackage(msg)
 	if err != nil {
 		return err
 	}
-	prefixWithoutParaID := strings.TrimPrefix(prefixSuffix[0], strings.TrimPrefix(paraIDHex, "0x"))
-	prefix, err := hex.DecodeString(prefixWithoutParaID)
+	if !relay {
+		return nil
+	}
+
+	msg, err = wr.refreshMessagePackage(options.Context, msg)
 	if err != nil {
 		return err
 	}
-	suffix, err := hex.DecodeString(prefixSuffix[1])
-	if err != nil {
+
+	err = wr.writeChannel(options, msg)
+
+	var revertErr *ethereum.RevertError
+	if !errors.As(err, &revertErr) {
 		return err
 	}
 
-	paraVerifyInput := basic.ParachainLightClientParachainVerifyInput{
-		OwnParachainHeadPrefixBytes: prefix,
-		OwnParachainHeadSuffixBytes: suffix,
-		ParachainHeadProof:          paraHeadProof,
+	if revertErr.Class == ethereum.RevertNonceUsed {
+		revertErr.Class, err = wr.classifyNonceRevert(options, msg)
+		if err != nil {
+			return err
+		}
 	}
 
-	beefyMMRLeafPartial := basic.ParachainLightClientBeefyMMRLeafPartial{
-		Version:              uint8(msgPackage.simplifiedMMRProof.Leaf.Version),
-		ParentNumber:         uint32(msgPackage.simplifiedMMRProof.Leaf.ParentNumberAndHash.ParentNumber),
-		ParentHash:           msgPackage.simplifiedMMRProof.Leaf.ParentNumberAndHash.Hash,
-		NextAuthoritySetId:   uint64(msgPackage.simplifiedMMRProof.Leaf.BeefyNextAuthoritySet.ID),
-		NextAuthoritySetLen:  uint32(msgPackage.simplifiedMMRProof.Leaf.BeefyNextAuthoritySet.Len),
-		NextAuthoritySetRoot: msgPackage.simplifiedMMRProof.Leaf.BeefyNextAuthoritySet.Root,
+	logger := log.WithFields(log.Fields{
+		"commitmentHash": msg.commitmentHash.Hex(),
+		"paraBlock":      msg.paraHead.Number,
+		"reason":         revertErr.Reason,
+		"class":          revertErr.Class.String(),
+	})
+
+	switch revertErr.Class {
+	case ethereum.RevertStaleRoot:
+		logger.Info("Proofs for message package are stale, regenerating proofs")
+		rebuilt, err := wr.builder.RebuildMessagePackage(options.Context, msg)
+		if err != nil {
+			logger.WithError(err).Error("Failed to regenerate proofs for message package")
+			return err
+		}
+		err = wr.writeChannel(options, rebuilt)
+		if errors.As(err, &revertErr) && revertErr.Class == ethereum.RevertStaleRoot {
+			// The next BEEFY update will cause the package to be rebuilt by the beefy listener
+			logger.WithError(err).Warn("Message package with regenerated proofs still fails simulation, skipping")
+			return nil
+		}
+		return err
+	case ethereum.RevertNonceUsed:
+		logger.Info("Messages in package have already been delivered, skipping")
+		return nil
+	case ethereum.RevertNonceGap:
+		// Not counted against the package, since it is the earlier package on the channel which is failing
+		logger.Error("ALERT: Earlier messages on the channel have not been delivered, message package will not be sent")
+		return nil
+	default:
+		logger.Error("ALERT: Message package fails simulation and will not be sent")
+		return revertErr
 	}
+}
 
-	var merkleProofItems [][32]byte
-	for _, proofItem := range msgPackage.simplifiedMMRProof.MerkleProofItems {
-		merkleProofItems = append(merkleProofItems, proofItem)
+// The inbound channels revert with the same reason whether the package's messages were already
+// delivered or earlier messages are missing, so the package's first nonce is compared with the
+// channel's nonce to tell them apart
+func (wr *EthereumChannelWriter) classifyNonceRevert(
+	options *bind.TransactOpts,
+	msg *MessagePackage,
+) (ethereum.RevertClass, error) {
+	channel, ok := wr.channels[msg.channelID]
+	if !ok {
+		return ethereum.RevertUnknown, fmt.Errorf("unsupported channel %v", msg.channelID)
 	}
 
-	simplifiedMMRProof := basic.SimplifiedMMRProof{
-		MerkleProofItems:         merkleProofItems,
-		MerkleProofOrderBitField: msgPackage.simplifiedMMRProof.MerkleProofOrder,
+	nonces, err := channel.DecodeNonces(msg.commitmentData)
+	if err != nil {
+		log.WithError(err).Error("Failed to decode commitment messages")
+		return ethereum.RevertUnknown, err
+	}
+	if len(nonces) == 0 {
+		return ethereum.RevertNonceUsed, nil
 	}
 
-	err = wr.logBasicTx(messages, paraVerifyInput,
-		beefyMMRLeafPartial, simplifiedMMRProof,
-		msgPackage.paraHead, msgPackage.merkleProofData, msgPackage.simplifiedMMRProof.Leaf,
-		msgPackage.commitmentHash, msgPackage.paraId, msgPackage.mmrRootHash,
-	)
+	acceptedNonce, err := channel.InboundNonce(&bind.CallOpts{
+		Pending: true,
+		Context: options.Context,
+	})
 	if err != nil {
-		log.WithError(err).Error("Failed to log transaction input")
-		return err
+		log.WithError(err).Error("Failed to get nonce of inbound channel")
+		return ethereum.RevertUnknown, err
 	}
 
+	return ethereum.ClassifyNonceRevert(nonces[0], acceptedNonce), nil
+}
 
-	tx, err := wr.basicInboundChannel.Submit(options, messages, paraVerifyInput,
-		beefyMMRLeafPartial,
-		simplifiedMMRProof)
+// Checks the messages in the package against the filter. Commitments are delivered as a whole, so the
+// package is relayed if any of its messages is accepted.
+func (wr *EthereumChannelWriter) filterMessagePackage(msg *MessagePackage) (bool, error) {
+	channel, ok := wr.channels[msg.channelID]
+	if !ok {
+		return false, fmt.Errorf("unsupported channel %v", msg.channelID)
+	}
+
+	nonces, err := channel.DecodeNonces(msg.commitmentData)
 	if err != nil {
-		log.WithError(err).Error("Failed to submit transaction")
-		return err
+		log.WithError(err).Error("Failed to decode commitment messages")
+		return false, err
 	}
 
-	log.WithFields(log.Fields{
-		"txHash":  tx.Hash().Hex(),
-		"channel": "Basic",
-	}).Info("Transaction submitted")
+	targets, err := channel.DecodeTargets(msg.commitmentData)
+	if err != nil {
+		log.WithError(err).Error("Failed to decode commitment messages")
+		return false, err
+	}
 
-	return nil
+	relayPackage := false
+	for i, target := range targets {
+		target := target
+		relay, reason := wr.filter.Allows(&filter.Message{
+			Channel: channel.Name(),
+			Target:  &target,
+		})
+
+		filterDecisions.WithLabelValues(channel.Name(), filter.DecisionLabel(relay)).Inc()
+
+		log.WithFields(log.Fields{
+			"channel":        channel.Name(),
+			"nonce":          nonces[i],
+			"target":         target.Hex(),
+			"commitmentHash": msg.commitmentHash.Hex(),
+			"relay":          relay,
+			"reason":         reason,
+		}).Debug("Checked message against filter")
+
+		relayPackage = relayPackage || relay
+	}
+
+	if !relayPackage {
+		log.WithFields(log.Fields{
+			"channel":        channel.Name(),
+			"commitmentHash": msg.commitmentHash.Hex(),
+			"paraBlock":      msg.paraHead.Number,
+		}).Info("Skipping message package in which all messages are rejected by filter")
+	}
+
+	return relayPackage, nil
 }
 
-func (wr *EthereumChannelWriter) WriteIncentivizedChannel(
-	options *bind.TransactOpts,
-	msgPackage *MessagePackage,
-	msgs []parachain.IncentivizedOutboundChannelMessage,
-) error {
-	var messages []incentivized.IncentivizedInboundChannelMessage
-	for _, m := range msgs {
-		messages = append(messages,
-			incentivized.IncentivizedInboundChannelMessage{
-				Target:  m.Target,
-				Nonce:   m.Nonce,
-				Fee:     m.Fee.Int,
-				Payload: m.Payload,
-			},
-		)
-	}
-
-	paraHeadProof := incentivized.ParachainLightClientParachainHeadProof{
-		Pos:   big.NewInt(int64(msgPackage.merkleProofData.ProvenLeafIndex)),
-		Width: big.NewInt(int64(msgPackage.merkleProofData.NumberOfLeaves)),
-		Proof: msgPackage.merkleProofData.Proof,
-	}
-
-	ownParachainHeadBytes := msgPackage.merkleProofData.ProvenPreLeaf
-	ownParachainHeadBytesString := hex.EncodeToString(ownParachainHeadBytes)
-	commitmentHashString := hex.EncodeToString(msgPackage.commitmentHash[:])
-	prefixSuffix := strings.Split(ownParachainHeadBytesString, commitmentHashString)
-	if len(prefixSuffix) != 2 {
-		return errors.New("error splitting parachain header into prefix and suffix")
-	}
-	paraIDHex, err := gsrpcTypes.EncodeToHexString(msgPackage.paraId)
+// Rebuilds the message package if its proofs were generated against an MMR root which is
+// no longer the latest root held by the light client
+func (wr *EthereumChannelWriter) refreshMessagePackage(
+	ctx context.Context,
+	msg *MessagePackage,
+) (*MessagePackage, error) {
+	latestMMRRoot, err := wr.beefyLightClient.LatestMMRRoot(&bind.CallOpts{
+		Pending: false,
+		Context: ctx,
+	})
 	if err != nil {
-		return err
+		log.WithError(err).Error("Failed to get latest MMR root from light client")
+		return nil, err
 	}
-	prefixWithoutParaID := strings.TrimPrefix(prefixSuffix[0], strings.TrimPrefix(paraIDHex, "0x"))
-	prefix, err := hex.DecodeString(prefixWithoutParaID)
-	if err != nil {
-		return err
+
+	if msg.mmrRootHash == gsrpcTypes.Hash(latestMMRRoot) {
+		return msg, nil
 	}
-	suffix, err := hex.DecodeString(prefixSuffix[1])
+
+	logger := log.WithFields(log.Fields{
+		"commitmentHash": msg.commitmentHash.Hex(),
+		"paraBlock":      msg.paraHead.Number,
+		"packageMMRRoot": msg.mmrRootHash.Hex(),
+		"latestMMRRoot":  gsrpcTypes.Hash(latestMMRRoot).Hex(),
+	})
+	logger.Info("Message package was built for a stale MMR root, regenerating proofs")
+
+	rebuilt, err := wr.builder.RebuildMessagePackage(ctx, msg)
 	if err != nil {
-		return err
+		logger.WithError(err).Error("Failed to regenerate proofs for message package")
+		return nil, err
 	}
 
-	paraVerifyInput := incentivized.ParachainLightClientParachainVerifyInput{
-		OwnParachainHeadPrefixBytes: prefix,
-		OwnParachainHeadSuffixBytes: suffix,
-		ParachainHeadProof:          paraHeadProof,
+	if rebuilt.mmrRootHash != gsrpcTypes.Hash(latestMMRRoot) {
+		// The light client may have been updated while the proofs were regenerated. Simulation
+		// will catch the stale root and trigger another rebuild.
+		logger.WithField("rebuiltMMRRoot", rebuilt.mmrRootHash.Hex()).
+			Warn("Regenerated message package does not match the light client's latest MMR root")
+	}
+
+	return rebuilt, nil
+}
+
+// Sends the messages in the package to the inbound channel for the package's channel on Ethereum
+func (wr *EthereumChannelWriter) writeChannel(
+	options *bind.TransactOpts,
+	msg *MessagePackage,
+) error {
+	channel, ok := wr.channels[msg.channelID]
+	if !ok {
+		return fmt.Errorf("unsupported channel %v", msg.channelID)
 	}
 
-	beefyMMRLeafPartial := incentivized.ParachainLightClientBeefyMMRLeafPartial{
-		Version:              uint8(msgPackage.simplifiedMMRProof.Leaf.Version),
-		ParentNumber:         uint32(msgPackage.simplifiedMMRProof.Leaf.ParentNumberAndHash.ParentNumber),
-		ParentHash:           msgPackage.simplifiedMMRProof.Leaf.ParentNumberAndHash.Hash,
-		NextAuthoritySetId:   uint64(msgPackage.simplifiedMMRProof.Leaf.BeefyNextAuthoritySet.ID),
-		NextAuthoritySetLen:  uint32(msgPackage.simplifiedMMRProof.Leaf.BeefyNextAuthoritySet.Len),
-		NextAuthoritySetRoot: msgPackage.simplifiedMMRProof.Leaf.BeefyNextAuthoritySet.Root,
+	proof, err := NewParachainProof(msg)
+	if err != nil {
+		log.WithError(err).Error("Failed to build parachain proof")
+		return err
 	}
 
-	var merkleProofItems [][32]byte
-	for _, proofItem := range msgPackage.simplifiedMMRProof.MerkleProofItems {
-		merkleProofItems = append(merkleProofItems, proofItem)
+	if feeChannel, ok := channel.(FeeChannel); ok && wr.profitability != nil {
+		profitable, err := wr.checkProfitability(options, feeChannel, msg, proof)
+		if err != nil {
+			return err
+		}
+		if !profitable {
+			return errMessagePackageDeferred
+		}
 	}
 
-	simplifiedMMRProof := incentivized.SimplifiedMMRProof{
-		MerkleProofItems:         merkleProofItems,
-		MerkleProofOrderBitField: msgPackage.simplifiedMMRProof.MerkleProofOrder,
+	messages, err := channel.LogMessages(msg.commitmentData)
+	if err != nil {
+		log.WithError(err).Error("Failed to decode commitment messages")
+		return err
 	}
 
-	err = wr.logIncentivizedTx(messages, paraVerifyInput,
-		beefyMMRLeafPartial, simplifiedMMRProof,
-		msgPackage.paraHead, msgPackage.merkleProofData, msgPackage.simplifiedMMRProof.Leaf,
-		msgPackage.commitmentHash, msgPackage.paraId, msgPackage.mmrRootHash,
-	)
+	err = wr.logSubmitTx(channel, messages, proof, msg)
 	if err != nil {
 		log.WithError(err).Error("Failed to log transaction input")
 		return err
 	}
 
-	tx, err := wr.incentivizedInboundChannel.Submit(options, messages,
-		paraVerifyInput, beefyMMRLeafPartial,
-		simplifiedMMRProof)
+	tx, err := channel.Submit(options, msg.commitmentData, proof)
 	if err != nil {
-		log.WithError(err).Error("Failed to submit transaction")
+		log.WithError(err).WithField("channel", channel.Name()).Error("Failed to write channel")
 		return err
 	}
+	transactionsSent.WithLabelValues(channel.Name()).Inc()
 
 	log.WithFields(log.Fields{
 		"txHash":  tx.Hash().Hex(),
-		"channel": "Incentivized",
+		"channel": channel.Name(),
 	}).Info("Transaction submitted")
 
+	err = wr.tracker.Track(channel, msg.commitmentData, tx.Hash())
+	if err != nil {
+		log.WithError(err).Error("Failed to record submitted messages")
+		return err
+	}
+
 	return nil
 }
 
-func (wr *EthereumChannelWriter) WriteChannel(
-	options *bind.TransactOpts,
-	msg *MessagePackage,
-) error {
-	if msg.channelID.IsBasic {
-		var outboundMessages []parachain.BasicOutboundChannelMessage
-		err := gsrpcTypes.DecodeFromBytes(msg.commitmentData, &outboundMessages)
-		if err != nil {
-			log.WithError(err).Error("Failed to decode commitment messages")
-			return err
-		}
-		err = wr.WriteBasicChannel(options, msg, outboundMessages)
-		if err != nil {
-			log.WithError(err).Error("Failed to write basic channel")
-			return err
-		}
+// Returns the gas price which a transaction sent now is expected to pay. Under EIP-1559 that is the
+// base fee plus the tip, which is usually well below the fee cap.
+func (wr *EthereumChannelWriter) expectedGasPrice(options *bind.TransactOpts) (*big.Int, error) {
+	client := wr.conn.GetClient()
 
+	head, err := client.HeaderByNumber(options.Context, nil)
+	if err != nil {
+		return nil, err
 	}
-	if msg.channelID.IsIncentivized {
-		var outboundMessages []parachain.IncentivizedOutboundChannelMessage
-		err := gsrpcTypes.DecodeFromBytes(msg.commitmentData, &outboundMessages)
+	if head.BaseFee == nil {
+		gasPrice, err := client.SuggestGasPrice(options.Context)
 		if err != nil {
-			log.WithError(err).Error("Failed to decode commitment messages")
-			return err
+			return nil, err
 		}
-		err = wr.WriteIncentivizedChannel(options, msg, outboundMessages)
+		return capGasPrice(gasPrice, options.GasFeeCap), nil
+	}
+
+	tip := options.GasTipCap
+	if tip == nil {
+		tip, err = client.SuggestGasTipCap(options.Context)
 		if err != nil {
-			log.WithError(err).Error("Failed to write incentivized channel")
-			return err
+			return nil, err
 		}
 	}
+	return capGasPrice(new(big.Int).Add(head.BaseFee, tip), options.GasFeeCap), nil
+}
 
-	return nil
+func capGasPrice(gasPrice, feeCap *big.Int) *big.Int {
+	if feeCap != nil && gasPrice.Cmp(feeCap) > 0 {
+		return feeCap
+	}
+	return gasPrice
+}
+
+// Checks whether the fees paid by the messages in the package cover the estimated cost of delivering them
+func (wr *EthereumChannelWriter) checkProfitability(
+	options *bind.TransactOpts,
+	channel FeeChannel,
+	msg *MessagePackage,
+	proof *ParachainProof,
+) (bool, error) {
+	fee, err := channel.TotalFee(msg.commitmentData)
+	if err != nil {
+		log.WithError(err).Error("Failed to decode commitment fees")
+		return false, err
+	}
+
+	gas, err := channel.EstimateSubmitGas(options, msg.commitmentData, proof)
+	if err != nil {
+		return false, err
+	}
+
+	gasPrice, err := wr.expectedGasPrice(options)
+	if err != nil {
+		log.WithError(err).Error("Failed to get gas price")
+		return false, err
+	}
+
+	decision, err := wr.profitability.Check(fee, gas, gasPrice)
+	if err != nil {
+		log.WithError(err).Error("Failed to check profitability of message package")
+		return false, err
+	}
+
+	profitabilityDecisions.WithLabelValues(channel.Name(), profitabilityDecisionLabel(decision.Profitable)).Inc()
+
+	logger := log.WithFields(log.Fields{
+		"channel":        channel.Name(),
+		"commitmentHash": msg.commitmentHash.Hex(),
+		"paraBlock":      msg.paraHead.Number,
+		"fee":            decision.Fee.String(),
+		"cost":           decision.Cost.String(),
+		"minimumFee":     decision.MinimumFee.String(),
+		"gas":            gas,
+		"gasPrice":       gasPrice.String(),
+		"ethPrice":       decision.Price.FloatString(6),
+	})
+	if decision.Profitable {
+		logger.Info("Message package fees cover the cost of delivery")
+	} else {
+		logger.Info("Message package fees do not cover the cost of delivery, deferring")
+	}
+
+	return decision.Profitable, nil
 }
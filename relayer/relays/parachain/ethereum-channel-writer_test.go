@@ -0,0 +1,39 @@
+package parachain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+)
+
+func TestDeferMessagePackageReplacesReemittedPackages(t *testing.T) {
+	wr := &EthereumChannelWriter{
+		deferred: make(map[parachain.ChannelID][]*MessagePackage),
+	}
+
+	first := &MessagePackage{channelID: incentivizedChannelID, commitmentHash: types.NewH256([]byte{1})}
+	second := &MessagePackage{channelID: incentivizedChannelID, commitmentHash: types.NewH256([]byte{2})}
+	wr.deferMessagePackage(first)
+	wr.deferMessagePackage(second)
+
+	// Emitted again by the beefy listener with proofs for a newer MMR root
+	reemitted := &MessagePackage{
+		channelID:      incentivizedChannelID,
+		commitmentHash: first.commitmentHash,
+		mmrRootHash:    types.NewHash([]byte{9}),
+	}
+	wr.deferMessagePackage(reemitted)
+
+	queue := wr.deferred[incentivizedChannelID]
+	assert.Equal(t, []*MessagePackage{reemitted, second}, queue)
+}
+
+func TestCapGasPrice(t *testing.T) {
+	assert.Equal(t, big.NewInt(30), capGasPrice(big.NewInt(30), big.NewInt(100)))
+	assert.Equal(t, big.NewInt(100), capGasPrice(big.NewInt(130), big.NewInt(100)))
+	assert.Equal(t, big.NewInt(130), capGasPrice(big.NewInt(130), nil))
+}
@@ -0,0 +1,220 @@
+package parachain
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
+	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/contracts/incentivized"
+
+	gsrpcTypes "github.com/snowfork/go-substrate-rpc-client/v3/types"
+)
+
+// Index of the incentivized channel in the runtime's ChannelId enum
+const incentivizedChannelID parachain.ChannelID = 1
+
+func init() {
+	registerChannel(incentivizedChannelID, "incentivized", newIncentivizedChannel)
+}
+
+type IncentivizedChannel struct {
+	contract  *incentivized.IncentivizedInboundChannel
+	simulator *ethereum.Simulator
+}
+
+func newIncentivizedChannel(conn *ethereum.Connection, address common.Address) (Channel, error) {
+	contract, err := incentivized.NewIncentivizedInboundChannel(address, conn.GetClient())
+	if err != nil {
+		return nil, err
+	}
+
+	simulator, err := ethereum.NewSimulator(conn, address, incentivized.IncentivizedInboundChannelABI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IncentivizedChannel{
+		contract:  contract,
+		simulator: simulator,
+	}, nil
+}
+
+func (ch *IncentivizedChannel) ID() parachain.ChannelID {
+	return incentivizedChannelID
+}
+
+func (ch *IncentivizedChannel) Name() string {
+	return "incentivized"
+}
+
+func (ch *IncentivizedChannel) OutboundModule() string {
+	return "IncentivizedOutboundModule"
+}
+
+func (ch *IncentivizedChannel) InboundNonce(opts *bind.CallOpts) (uint64, error) {
+	return ch.contract.Nonce(opts)
+}
+
+func (ch *IncentivizedChannel) decodeMessages(data []byte) ([]incentivized.IncentivizedInboundChannelMessage, error) {
+	var outboundMessages []parachain.IncentivizedOutboundChannelMessage
+	err := gsrpcTypes.DecodeFromBytes(data, &outboundMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []incentivized.IncentivizedInboundChannelMessage
+	for _, m := range outboundMessages {
+		messages = append(messages,
+			incentivized.IncentivizedInboundChannelMessage{
+				Target:  m.Target,
+				Nonce:   m.Nonce,
+				Fee:     m.Fee.Int,
+				Payload: m.Payload,
+			},
+		)
+	}
+	return messages, nil
+}
+
+func (ch *IncentivizedChannel) DecodeNonces(data []byte) ([]uint64, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonces []uint64
+	for _, m := range messages {
+		nonces = append(nonces, m.Nonce)
+	}
+	return nonces, nil
+}
+
+func (ch *IncentivizedChannel) DecodeTargets(data []byte) ([]common.Address, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []common.Address
+	for _, m := range messages {
+		targets = append(targets, m.Target)
+	}
+	return targets, nil
+}
+
+func (ch *IncentivizedChannel) LogMessages(data []byte) (interface{}, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var messagesLog []IncentivizedInboundChannelMessageLog
+	for _, item := range messages {
+		messagesLog = append(messagesLog, IncentivizedInboundChannelMessageLog{
+			Target:   item.Target,
+			Nonce:    item.Nonce,
+			Fee:      item.Fee,
+			Payload:  "0x" + hex.EncodeToString(item.Payload),
+			Transfer: describeTransfer(item.Payload),
+		})
+	}
+	return messagesLog, nil
+}
+
+type incentivizedSubmitInput struct {
+	messages            []incentivized.IncentivizedInboundChannelMessage
+	paraVerifyInput     incentivized.ParachainLightClientParachainVerifyInput
+	beefyMMRLeafPartial incentivized.ParachainLightClientBeefyMMRLeafPartial
+	simplifiedMMRProof  incentivized.SimplifiedMMRProof
+}
+
+func (in *incentivizedSubmitInput) args() []interface{} {
+	return []interface{}{in.messages, in.paraVerifyInput, in.beefyMMRLeafPartial, in.simplifiedMMRProof}
+}
+
+func (ch *IncentivizedChannel) submitInput(data []byte, proof *ParachainProof) (*incentivizedSubmitInput, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &incentivizedSubmitInput{
+		messages: messages,
+		paraVerifyInput: incentivized.ParachainLightClientParachainVerifyInput{
+			OwnParachainHeadPrefixBytes: proof.ParaVerifyInput.OwnParachainHeadPrefixBytes,
+			OwnParachainHeadSuffixBytes: proof.ParaVerifyInput.OwnParachainHeadSuffixBytes,
+			ParachainHeadProof:          incentivized.ParachainLightClientParachainHeadProof(proof.ParaVerifyInput.ParachainHeadProof),
+		},
+		beefyMMRLeafPartial: incentivized.ParachainLightClientBeefyMMRLeafPartial(proof.BeefyMMRLeafPartial),
+		simplifiedMMRProof:  incentivized.SimplifiedMMRProof(proof.SimplifiedMMRProof),
+	}, nil
+}
+
+func (ch *IncentivizedChannel) Submit(opts *bind.TransactOpts, data []byte, proof *ParachainProof) (*types.Transaction, error) {
+	input, err := ch.submitInput(data, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ch.simulator.Simulate(opts.Context, opts.From, "submit", input.args()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.contract.Submit(opts, input.messages, input.paraVerifyInput,
+		input.beefyMMRLeafPartial, input.simplifiedMMRProof)
+}
+
+// EstimateSubmitGas estimates the gas used to deliver the messages in the commitment data
+func (ch *IncentivizedChannel) EstimateSubmitGas(opts *bind.TransactOpts, data []byte, proof *ParachainProof) (uint64, error) {
+	input, err := ch.submitInput(data, proof)
+	if err != nil {
+		return 0, err
+	}
+
+	return ch.simulator.EstimateGas(opts.Context, opts.From, "submit", input.args()...)
+}
+
+// TotalFee returns the sum of the fees paid by the messages in the commitment data
+func (ch *IncentivizedChannel) TotalFee(data []byte) (*big.Int, error) {
+	messages, err := ch.decodeMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	total := big.NewInt(0)
+	for _, m := range messages {
+		total.Add(total, m.Fee)
+	}
+	return total, nil
+}
+
+func (ch *IncentivizedChannel) FilterMessageDispatched(opts *bind.FilterOpts) ([]MessageDispatched, error) {
+	iter, err := ch.contract.FilterMessageDispatched(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var events []MessageDispatched
+	for iter.Next() {
+		events = append(events, MessageDispatched{
+			Nonce:       iter.Event.Nonce,
+			Result:      iter.Event.Result,
+			TxHash:      iter.Event.Raw.TxHash,
+			BlockNumber: iter.Event.Raw.BlockNumber,
+		})
+	}
+
+	err = iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
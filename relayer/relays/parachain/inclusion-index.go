@@ -0,0 +1,142 @@
+package parachain
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// InclusionIndex follows finalized relay chain blocks and records the relay chain block
+// at which each parachain block became the para head of our parachain
+type InclusionIndex struct {
+	relaychainConn *relaychain.Connection
+	index          *store.Database
+	paraID         uint32
+}
+
+func NewInclusionIndex(
+	relaychainConn *relaychain.Connection,
+	index *store.Database,
+	paraID uint32,
+) *InclusionIndex {
+	return &InclusionIndex{
+		relaychainConn: relaychainConn,
+		index:          index,
+		paraID:         paraID,
+	}
+}
+
+func (ii *InclusionIndex) Start(ctx context.Context, eg *errgroup.Group) error {
+	eg.Go(func() error {
+		err := ii.followFinalizedBlocks(ctx)
+		if err != nil {
+			log.WithError(err).Error("Failed to index relay chain blocks")
+		}
+		return err
+	})
+
+	return nil
+}
+
+// Lookup returns the earliest known relay chain block at which the given parachain block was the para head
+func (ii *InclusionIndex) Lookup(paraBlock uint64) (uint64, bool, error) {
+	return ii.index.GetParaHeadInclusion(paraBlock)
+}
+
+// IndexRelayBlock records the para head of our parachain at the given relay chain block,
+// and returns the number of that para head
+func (ii *InclusionIndex) IndexRelayBlock(relayBlock uint64) (uint64, error) {
+	relayBlockHash, err := ii.relaychainConn.API().RPC.Chain.GetBlockHash(relayBlock)
+	if err != nil {
+		log.WithError(err).Error("Failed to get block hash")
+		return 0, err
+	}
+
+	paraHead, err := ii.relaychainConn.FetchFinalizedParaHead(relayBlockHash, ii.paraID)
+	if err != nil {
+		log.WithError(err).Error("Failed to get para head from relay chain")
+		return 0, err
+	}
+
+	paraBlock := uint64(paraHead.Number)
+
+	err = ii.index.PutParaHeadInclusion(paraBlock, relayBlock)
+	if err != nil {
+		log.WithError(err).Error("Failed to record para head inclusion")
+		return 0, err
+	}
+
+	return paraBlock, nil
+}
+
+func (ii *InclusionIndex) followFinalizedBlocks(ctx context.Context) error {
+	sub, err := ii.relaychainConn.API().RPC.Chain.SubscribeFinalizedHeads()
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			ii.relaychainConn.Disconnected()
+			return err
+		case header := <-sub.Chan():
+			err := ii.indexUpTo(ctx, uint64(header.Number))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Indexes all relay chain blocks since the last indexed block, up to the given finalized block.
+// On first start, indexing begins at the given finalized block.
+func (ii *InclusionIndex) indexUpTo(ctx context.Context, finalizedBlock uint64) error {
+	lastIndexedBlock, ok, err := ii.index.GetLastIndexedRelayBlock()
+	if err != nil {
+		return err
+	}
+
+	startBlock := finalizedBlock
+	if ok {
+		startBlock = lastIndexedBlock + 1
+	}
+
+	if startBlock < finalizedBlock {
+		log.WithFields(log.Fields{
+			"fromBlock": startBlock,
+			"toBlock":   finalizedBlock,
+		}).Info("Indexing relay chain blocks")
+	}
+
+	for relayBlock := startBlock; relayBlock <= finalizedBlock; relayBlock++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		paraBlock, err := ii.IndexRelayBlock(relayBlock)
+		if err != nil {
+			return err
+		}
+
+		err = ii.index.SetLastIndexedRelayBlock(relayBlock)
+		if err != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"relayBlock": relayBlock,
+			"paraBlock":  paraBlock,
+		}).Debug("Indexed relay chain block")
+	}
+
+	return nil
+}
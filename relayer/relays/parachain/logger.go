@@ -8,8 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
 	"github.com/snowfork/go-substrate-rpc-client/v3/types"
-	"github.com/snowfork/snowbridge/relayer/contracts/basic"
-	"github.com/snowfork/snowbridge/relayer/contracts/incentivized"
+	"github.com/snowfork/snowbridge/relayer/apps"
 	"github.com/snowfork/snowbridge/relayer/crypto/keccak"
 )
 
@@ -35,16 +34,28 @@ type BeefyMMRLeafPartialLog struct {
 }
 
 type BasicInboundChannelMessageLog struct {
-	Target  common.Address `json:"target"`
-	Nonce   uint64         `json:"nonce"`
-	Payload string         `json:"payload"`
+	Target   common.Address `json:"target"`
+	Nonce    uint64         `json:"nonce"`
+	Payload  string         `json:"payload"`
+	Transfer string         `json:"transfer,omitempty"`
 }
 
 type IncentivizedInboundChannelMessageLog struct {
-	Target  common.Address `json:"target"`
-	Nonce   uint64         `json:"nonce"`
-	Fee     *big.Int       `json:"fee"`
-	Payload string         `json:"payload"`
+	Target   common.Address `json:"target"`
+	Nonce    uint64         `json:"nonce"`
+	Fee      *big.Int       `json:"fee"`
+	Payload  string         `json:"payload"`
+	Transfer string         `json:"transfer,omitempty"`
+}
+
+// Describes the transfer in a message payload, or returns an empty string if the payload is not
+// sent by a known application
+func describeTransfer(payload []byte) string {
+	transfer, err := apps.DecodeParachainPayload(payload)
+	if err != nil {
+		return ""
+	}
+	return transfer.String()
 }
 
 type SimplifiedMMRProofLog struct {
@@ -54,37 +65,30 @@ type SimplifiedMMRProofLog struct {
 	MerkleProofOrder   uint64 `json:"MerkleProofOrder"`
 }
 
-type BasicSubmitInput struct {
-	Messages            []BasicInboundChannelMessageLog `json:"_messages"`
-	ParaVerifyInput     ParaVerifyInputLog              `json:"_paraVerifyInput"`
-	BeefyMMRLeafPartial BeefyMMRLeafPartialLog          `json:"_beefyMMRLeafPartial"`
-	SimplifiedMMRProof SimplifiedMMRProofLog `json:"_beefyMMRSimplifiedProof"`
-}
-
-type IncentivizedSubmitInput struct {
-	Messages            []IncentivizedInboundChannelMessageLog `json:"_messages"`
-	ParaVerifyInput     ParaVerifyInputLog                     `json:"_paraVerifyInput"`
-	BeefyMMRLeafPartial BeefyMMRLeafPartialLog                 `json:"_beefyMMRLeafPartial"`
-	SimplifiedMMRProof SimplifiedMMRProofLog `json:"_beefyMMRSimplifiedProof"`
+type SubmitInput struct {
+	Messages            interface{}            `json:"_messages"`
+	ParaVerifyInput     ParaVerifyInputLog     `json:"_paraVerifyInput"`
+	BeefyMMRLeafPartial BeefyMMRLeafPartialLog `json:"_beefyMMRLeafPartial"`
+	SimplifiedMMRProof  SimplifiedMMRProofLog  `json:"_beefyMMRSimplifiedProof"`
 }
 
-func (wr *EthereumChannelWriter) logBasicTx(
-	messages []basic.BasicInboundChannelMessage,
-	paraVerifyInput basic.ParachainLightClientParachainVerifyInput,
-	beefyMMRLeafPartial basic.ParachainLightClientBeefyMMRLeafPartial,
-	beefyMMRSimplifiedProof basic.SimplifiedMMRProof,
-	paraHead types.Header, merkleProofData MerkleProofData, mmrLeaf types.MMRLeaf,
-	commitmentHash types.H256, paraID uint32, mmrRootHash types.Hash,
+func (wr *EthereumChannelWriter) logSubmitTx(
+	channel Channel,
+	messages interface{},
+	proof *ParachainProof,
+	msgPackage *MessagePackage,
 ) error {
+	paraVerifyInput := proof.ParaVerifyInput
+	beefyMMRLeafPartial := proof.BeefyMMRLeafPartial
+	beefyMMRSimplifiedProof := proof.SimplifiedMMRProof
+
+	paraHead := msgPackage.paraHead
+	merkleProofData := msgPackage.merkleProofData
+	mmrLeaf := msgPackage.simplifiedMMRProof.Leaf
+	commitmentHash := msgPackage.commitmentHash
+	paraID := msgPackage.paraId
+	mmrRootHash := msgPackage.mmrRootHash
 
-	var basicMessagesLog []BasicInboundChannelMessageLog
-	for _, item := range messages {
-		basicMessagesLog = append(basicMessagesLog, BasicInboundChannelMessageLog{
-			Target:  item.Target,
-			Nonce:   item.Nonce,
-			Payload: "0x" + hex.EncodeToString(item.Payload),
-		})
-	}
 	var paraHeadProofString []string
 	for _, item := range paraVerifyInput.ParachainHeadProof.Proof {
 		paraHeadProofString = append(paraHeadProofString, "0x"+hex.EncodeToString(item[:]))
@@ -95,9 +99,8 @@ func (wr *EthereumChannelWriter) logBasicTx(
 		beefyMMRMerkleProofItems = append(beefyMMRMerkleProofItems, "0x"+hex.EncodeToString(item[:]))
 	}
 
-
-	input := &BasicSubmitInput{
-		Messages: basicMessagesLog,
+	input := &SubmitInput{
+		Messages: messages,
 		ParaVerifyInput: ParaVerifyInputLog{
 			OwnParachainHeadPrefixBytes: "0x" + hex.EncodeToString(paraVerifyInput.OwnParachainHeadPrefixBytes),
 			OwnParachainHeadSuffixBytes: "0x" + hex.EncodeToString(paraVerifyInput.OwnParachainHeadSuffixBytes),
@@ -116,8 +119,8 @@ func (wr *EthereumChannelWriter) logBasicTx(
 			NextAuthoritySetRoot: "0x" + hex.EncodeToString(beefyMMRLeafPartial.NextAuthoritySetRoot[:]),
 		},
 		SimplifiedMMRProof: SimplifiedMMRProofLog{
-			MerkleProofItems:        beefyMMRMerkleProofItems,
-			MerkleProofOrder:        beefyMMRSimplifiedProof.MerkleProofOrderBitField,
+			MerkleProofItems: beefyMMRMerkleProofItems,
+			MerkleProofOrder: beefyMMRSimplifiedProof.MerkleProofOrderBitField,
 		},
 	}
 	b, err := json.Marshal(input)
@@ -142,6 +145,7 @@ func (wr *EthereumChannelWriter) logBasicTx(
 	}
 
 	log.WithFields(log.Fields{
+		"channel":                     channel.Name(),
 		"input":                       string(b),
 		"commitmentHash":              "0x" + hex.EncodeToString(commitmentHash[:]),
 		"paraHeadProofRootMerkleLeaf": "0x" + hex.EncodeToString(mmrLeaf.ParachainHeads[:]),
@@ -168,102 +172,3 @@ func (wr *EthereumChannelWriter) logBasicTx(
 
 	return nil
 }
-
-func (wr *EthereumChannelWriter) logIncentivizedTx(
-	messages []incentivized.IncentivizedInboundChannelMessage,
-	paraVerifyInput incentivized.ParachainLightClientParachainVerifyInput,
-	beefyMMRLeafPartial incentivized.ParachainLightClientBeefyMMRLeafPartial,
-	beefyMMRSimplifiedProof incentivized.SimplifiedMMRProof,
-	paraHead types.Header, merkleProofData MerkleProofData, mmrLeaf types.MMRLeaf,
-	commitmentHash types.H256, paraID uint32, mmrRootHash types.Hash,
-) error {
-	var incentivizedMessagesLog []IncentivizedInboundChannelMessageLog
-	for _, item := range messages {
-		incentivizedMessagesLog = append(incentivizedMessagesLog, IncentivizedInboundChannelMessageLog{
-			Target:  item.Target,
-			Nonce:   item.Nonce,
-			Fee:     item.Fee,
-			Payload: "0x" + hex.EncodeToString(item.Payload),
-		})
-	}
-
-	var paraHeadProofString []string
-	for _, item := range paraVerifyInput.ParachainHeadProof.Proof {
-		paraHeadProofString = append(paraHeadProofString, "0x"+hex.EncodeToString(item[:]))
-	}
-
-	var beefyMMRMerkleProofItems []string
-	for _, item := range beefyMMRSimplifiedProof.MerkleProofItems {
-		beefyMMRMerkleProofItems = append(beefyMMRMerkleProofItems, "0x"+hex.EncodeToString(item[:]))
-	}
-
-	input := &IncentivizedSubmitInput{
-		Messages: incentivizedMessagesLog,
-		ParaVerifyInput: ParaVerifyInputLog{
-			OwnParachainHeadPrefixBytes: "0x" + hex.EncodeToString(paraVerifyInput.OwnParachainHeadPrefixBytes),
-			OwnParachainHeadSuffixBytes: "0x" + hex.EncodeToString(paraVerifyInput.OwnParachainHeadSuffixBytes),
-			ParachainHeadProof: ParaHeadProofLog{
-				Pos:   paraVerifyInput.ParachainHeadProof.Pos,
-				Width: paraVerifyInput.ParachainHeadProof.Width,
-				Proof: paraHeadProofString,
-			},
-		},
-		BeefyMMRLeafPartial: BeefyMMRLeafPartialLog{
-			ParentNumber:         beefyMMRLeafPartial.ParentNumber,
-			ParentHash:           "0x" + hex.EncodeToString(beefyMMRLeafPartial.ParentHash[:]),
-			NextAuthoritySetId:   beefyMMRLeafPartial.NextAuthoritySetId,
-			NextAuthoritySetLen:  beefyMMRLeafPartial.NextAuthoritySetLen,
-			NextAuthoritySetRoot: "0x" + hex.EncodeToString(beefyMMRLeafPartial.NextAuthoritySetRoot[:]),
-		},
-		SimplifiedMMRProof: SimplifiedMMRProofLog{
-			MerkleProofItems:        beefyMMRMerkleProofItems,
-			MerkleProofOrder:        beefyMMRSimplifiedProof.MerkleProofOrderBitField,
-		},
-	}
-	b, err := json.Marshal(input)
-	if err != nil {
-		return err
-	}
-
-	mmrLeafEncoded, _ := types.EncodeToBytes(mmrLeaf)
-	mmrLeafOpaqueEncoded, _ := types.EncodeToHexString(mmrLeafEncoded)
-	mmrLeafOpaqueEncodedBytes, _ := types.EncodeToBytes(mmrLeafEncoded)
-	scaleParaId, _ := types.EncodeToHexString(paraID)
-	scaleParaHead, _ := types.EncodeToHexString(paraHead)
-	scaleParaHeadParentHash, _ := types.EncodeToHexString(paraHead.ParentHash)
-	scaleparaHeadNumber, _ := types.EncodeToHexString(paraHead.Number)
-	scaleparaHeadStateRoot, _ := types.EncodeToHexString(paraHead.StateRoot)
-	scaleparaHeadExtrinsicsRoot, _ := types.EncodeToHexString(paraHead.ExtrinsicsRoot)
-	scaleparaHeadDigest, _ := types.EncodeToHexString(paraHead.Digest)
-	var scaleDigestItems []string
-	for _, item := range paraHead.Digest {
-		scaleDigestItem, _ := types.EncodeToHexString(item)
-		scaleDigestItems = append(scaleDigestItems, scaleDigestItem)
-	}
-
-	log.WithFields(log.Fields{
-		"input":                       string(b),
-		"commitmentHash":              "0x" + hex.EncodeToString(commitmentHash[:]),
-		"paraHeadProofRootMerkleLeaf": "0x" + hex.EncodeToString(mmrLeaf.ParachainHeads[:]),
-		"mmrLeafOpaqueEncoded":        mmrLeafOpaqueEncoded,
-		"mmrRootHash":                 "0x" + hex.EncodeToString(mmrRootHash[:]),
-		"merkleProofData":             merkleProofData,
-		"scaleParaId":                 scaleParaId,
-		"scaleParaHead":               scaleParaHead,
-		"scaleParaHeadParentHash":     scaleParaHeadParentHash,
-		"scaleparaHeadNumber":         scaleparaHeadNumber,
-		"scaleparaHeadStateRoot":      scaleparaHeadStateRoot,
-		"scaleparaHeadExtrinsicsRoot": scaleparaHeadExtrinsicsRoot,
-		"scaleparaHeadDigest":         scaleparaHeadDigest,
-		"scaleDigestItems":            scaleDigestItems,
-	}).Info("Submitting tx")
-
-	hasher := &keccak.Keccak256{}
-
-	log.WithFields(log.Fields{
-		"mmrLeafOpaqueEncoded": mmrLeafOpaqueEncoded,
-		"hashedOpaqueLeaf":     "0x" + hex.EncodeToString(hasher.Hash(mmrLeafOpaqueEncodedBytes)),
-		"hashedLeaf":           "0x" + hex.EncodeToString(hasher.Hash(mmrLeafEncoded)),
-	}).Info("DAT LEAF")
-	return nil
-}
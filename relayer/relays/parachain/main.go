@@ -2,87 +2,197 @@ package parachain
 
 import (
 	"context"
+	"fmt"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
 	"github.com/snowfork/snowbridge/relayer/chain/ethereum"
 	"github.com/snowfork/snowbridge/relayer/chain/parachain"
+	"github.com/snowfork/snowbridge/relayer/chain/pool"
 	"github.com/snowfork/snowbridge/relayer/chain/relaychain"
-	"github.com/snowfork/snowbridge/relayer/crypto/secp256k1"
+	"github.com/snowfork/snowbridge/relayer/config"
+	"github.com/snowfork/snowbridge/relayer/crypto"
+	"github.com/snowfork/snowbridge/relayer/relays/filter"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain/store"
+	"github.com/snowfork/snowbridge/relayer/retry"
+
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// Number of finalized parachain blocks searched for a commitment to probe offchain indexing
+const offchainIndexingProbeDepth = 256
+
 type Relay struct {
 	config                *Config
+	connections           *pool.Pool
 	parachainConn         *parachain.Connection
 	relaychainConn        *relaychain.Connection
+	sourceEthereumConn    *ethereum.Connection
 	ethereumConn          *ethereum.Connection
 	ethereumChannelWriter *EthereumChannelWriter
 	beefyListener         *BeefyListener
+	deliveryTracker       *DeliveryTracker
+	index                 *store.Database
 }
 
-func NewRelay(config *Config, keypair *secp256k1.Keypair) (*Relay, error) {
+// NewRelay creates the relay. Its read-only relay chain and Ethereum connections are taken from the
+// pool, so that they can be shared with other relays.
+func NewRelay(config *Config, signer crypto.Signer, connections *pool.Pool) (*Relay, error) {
 	log.Info("Creating worker")
 
-	parachainConn := parachain.NewConnection(config.Source.Parachain.Endpoint, nil)
-	relaychainConn := relaychain.NewConnection(config.Source.Polkadot.Endpoint)
+	parachainConn := parachain.NewConnection(
+		config.Source.Parachain.Endpoint,
+		nil,
+		config.Source.Parachain.FallbackEndpoints...,
+	)
+	relaychainConn := connections.Relaychain(config.Source.Polkadot.Endpoint)
 
-	// TODO: This is used by both the source & sink. They should use separate connections
-	ethereumConn := ethereum.NewConnection(config.Sink.Ethereum.Endpoint, keypair)
+	// Older configurations only set the sink endpoint
+	sourceEthereumEndpoint := config.Source.Ethereum.Endpoint
+	if sourceEthereumEndpoint == "" {
+		sourceEthereumEndpoint = config.Sink.Ethereum.Endpoint
+	}
+	sourceEthereumConn := connections.Ethereum(sourceEthereumEndpoint)
+	ethereumConn := ethereum.NewConnection(config.Sink.Ethereum.Endpoint, signer)
 
 	// channel for messages from beefy listener to ethereum writer
 	var messagePackages = make(chan MessagePackage, 1)
 
-	ethereumChannelWriter, err := NewEthereumChannelWriter(
-		&config.Sink,
-		ethereumConn,
+	index := store.NewDatabase(config.Database.Path)
+
+	beefyListener := NewBeefyListener(
+		&config.Source,
+		sourceEthereumConn,
+		relaychainConn,
+		parachainConn,
 		messagePackages,
+		index,
 	)
+
+	deliveryTracker := NewDeliveryTracker(&config.Sink, ethereumConn, index)
+
+	messageFilter, err := filter.New(&config.Filter)
 	if err != nil {
 		return nil, err
 	}
 
-	beefyListener := NewBeefyListener(
-		&config.Source,
+	ethereumChannelWriter, err := NewEthereumChannelWriter(
+		&config.Sink,
 		ethereumConn,
-		relaychainConn,
-		parachainConn,
+		common.HexToAddress(config.Source.Contracts.BeefyLightClient),
 		messagePackages,
+		beefyListener,
+		deliveryTracker,
+		messageFilter,
+		index,
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Relay{
 		config:                config,
+		connections:           connections,
 		parachainConn:         parachainConn,
 		relaychainConn:        relaychainConn,
+		sourceEthereumConn:    sourceEthereumConn,
 		ethereumConn:          ethereumConn,
 		ethereumChannelWriter: ethereumChannelWriter,
 		beefyListener:         beefyListener,
+		deliveryTracker:       deliveryTracker,
+		index:                 index,
+	}, nil
+}
+
+// Returns the configured commitment with which nodes are probed for offchain indexing, or nil if there is none
+func parseProbeCommitment(commitment *config.CommitmentConfig) (*parachain.Commitment, error) {
+	if commitment == nil {
+		return nil, nil
+	}
+
+	hash, err := hexutil.Decode(commitment.Hash)
+	if err != nil || len(hash) != len(types.H256{}) {
+		return nil, retry.Fatal(fmt.Errorf("invalid probe commitment hash '%s'", commitment.Hash))
+	}
+
+	return &parachain.Commitment{
+		ChannelID: parachain.ChannelID(commitment.ChannelID),
+		Hash:      types.NewH256(hash),
 	}, nil
 }
 
 func (relay *Relay) Start(ctx context.Context, eg *errgroup.Group) error {
+	// Each instance of the relay has its own parachain and signing connections. Pooled connections
+	// are left open for the other relays.
+	eg.Go(func() error {
+		<-ctx.Done()
+		relay.parachainConn.Close()
+		relay.ethereumConn.Close()
+		return nil
+	})
+
 	err := relay.parachainConn.Connect(ctx)
 	if err != nil {
 		return err
 	}
 
+	probeCommitment, err := parseProbeCommitment(relay.config.Source.Parachain.ProbeCommitment)
+	if err != nil {
+		return err
+	}
+
+	// Reject nodes which can't serve commitments before any work starts
+	err = relay.parachainConn.ProbeOffchainIndexing(offchainIndexingProbeDepth, probeCommitment)
+	if err != nil {
+		return err
+	}
+
+	err = relay.connections.Connect(ctx, relay.sourceEthereumConn)
+	if err != nil {
+		return err
+	}
+
 	err = relay.ethereumConn.Connect(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = relay.relaychainConn.Connect(ctx)
+	err = relay.connections.Connect(ctx, relay.relaychainConn)
 	if err != nil {
 		return err
 	}
 
+	err = relay.index.Initialize()
+	if err != nil {
+		return err
+	}
+
+	eg.Go(func() error {
+		<-ctx.Done()
+		err := relay.index.Close()
+		if err != nil {
+			log.WithError(err).Error("Unable to close commitment index")
+		}
+		return nil
+	})
+
 	log.Info("Starting beefy listener")
 	err = relay.beefyListener.Start(ctx, eg)
 	if err != nil {
 		return err
 	}
 
+	log.Info("Starting delivery tracker")
+	err = relay.deliveryTracker.Start(ctx, eg)
+	if err != nil {
+		return err
+	}
+
 	log.Info("Starting ethereum writer")
 	err = relay.ethereumChannelWriter.Start(ctx, eg)
 	if err != nil {
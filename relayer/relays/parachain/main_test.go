@@ -0,0 +1,26 @@
+package parachain
+
+import (
+	"testing"
+
+	"github.com/snowfork/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/snowfork/snowbridge/relayer/config"
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+func TestParseProbeCommitment(t *testing.T) {
+	commitment, err := parseProbeCommitment(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, commitment)
+
+	hash := "0x0707070707070707070707070707070707070707070707070707070707070707"
+	commitment, err = parseProbeCommitment(&config.CommitmentConfig{ChannelID: 1, Hash: hash})
+	assert.NoError(t, err)
+	assert.Equal(t, incentivizedChannelID, commitment.ChannelID)
+	assert.Equal(t, types.MustHexDecodeString(hash), commitment.Hash[:])
+
+	_, err = parseProbeCommitment(&config.CommitmentConfig{Hash: "0x0707"})
+	assert.True(t, retry.IsFatal(err))
+}
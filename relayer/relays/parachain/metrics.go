@@ -0,0 +1,85 @@
+package parachain
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	messagesDispatched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "messages_dispatched_total",
+		Help:      "Messages dispatched by the inbound channels on Ethereum, by result.",
+	}, []string{"channel", "result"})
+
+	profitabilityDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "profitability_decisions_total",
+		Help:      "Profitability checks of commitments, by decision.",
+	}, []string{"channel", "decision"})
+
+	filterDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "filter_decisions_total",
+		Help:      "Messages checked against the message filter, by decision.",
+	}, []string{"channel", "decision"})
+
+	deferredMessagePackages = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "deferred_message_packages",
+		Help:      "Message packages waiting for their fees to cover the cost of delivery.",
+	}, []string{"channel"})
+
+	deadLetteredMessagePackages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "dead_lettered_message_packages_total",
+		Help:      "Message packages which were dead-lettered after repeatedly failing to be delivered.",
+	}, []string{"channel"})
+
+	pausedChannels = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "paused_channels",
+		Help:      "Channels on which delivery is paused until a dead-lettered message package is replayed.",
+	}, []string{"channel"})
+
+	channelNonce = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "channel_nonce",
+		Help:      "Latest known nonce of the outbound channel on the parachain and the inbound channel on Ethereum.",
+	}, []string{"channel", "chain"})
+
+	transactionsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "transactions_sent_total",
+		Help:      "Transactions sent to the inbound channels on Ethereum.",
+	}, []string{"channel"})
+
+	gasUsed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "parachain_relay",
+		Name:      "gas_used_total",
+		Help:      "Gas used by the transactions sent to the inbound channels on Ethereum.",
+	}, []string{"channel"})
+)
+
+func dispatchResultLabel(result bool) string {
+	if result {
+		return "success"
+	}
+	return "failure"
+}
+
+func profitabilityDecisionLabel(profitable bool) string {
+	if profitable {
+		return "deliver"
+	}
+	return "defer"
+}
@@ -0,0 +1,168 @@
+package parachain
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+const (
+	// Fees on the incentivized channel are paid in ether, denominated in wei
+	etherDecimals = 18
+	// Fees paid in DOT are denominated in planck
+	dotDecimals = 10
+	// Seconds between checks of deferred message packages
+	defaultRecheckInterval = 60
+)
+
+var errMessagePackageDeferred = errors.New("message package deferred until its fees cover the cost of delivery")
+
+// PriceSource provides the exchange rate used to convert the cost of delivering messages on Ethereum
+// into the currency in which message fees are paid
+type PriceSource interface {
+	// EthPrice returns the price of 1 ETH in the fee currency
+	EthPrice() (*big.Rat, error)
+}
+
+// IdentityPriceSource is used when message fees are paid in ether, as they are by the runtimes,
+// whose FeeCurrency is SingleAssetAdaptor<Runtime, Ether>
+type IdentityPriceSource struct{}
+
+func (IdentityPriceSource) EthPrice() (*big.Rat, error) {
+	return big.NewRat(1, 1), nil
+}
+
+// StaticPriceSource returns a price fixed in the relay configuration
+type StaticPriceSource struct {
+	price *big.Rat
+}
+
+func NewStaticPriceSource(price string) (*StaticPriceSource, error) {
+	parsed, err := parsePrice(price)
+	if err != nil {
+		return nil, err
+	}
+	return &StaticPriceSource{price: parsed}, nil
+}
+
+func (s *StaticPriceSource) EthPrice() (*big.Rat, error) {
+	return new(big.Rat).Set(s.price), nil
+}
+
+// FilePriceSource reads the price from a local file holding a single decimal number. The file is
+// read on each call, so that it can be updated by an external price feed while the relay is running.
+type FilePriceSource struct {
+	path string
+}
+
+func NewFilePriceSource(path string) *FilePriceSource {
+	return &FilePriceSource{path: path}
+}
+
+func (s *FilePriceSource) EthPrice() (*big.Rat, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return parsePrice(string(data))
+}
+
+func parsePrice(value string) (*big.Rat, error) {
+	price, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || price.Sign() <= 0 {
+		return nil, retry.Fatal(fmt.Errorf("invalid ETH/DOT price '%s'", strings.TrimSpace(value)))
+	}
+	return price, nil
+}
+
+// ProfitabilityDecision describes whether the fees paid by a commitment cover the cost of delivering it.
+// All amounts are denominated in the fee currency.
+type ProfitabilityDecision struct {
+	Fee        *big.Int
+	Cost       *big.Int
+	MinimumFee *big.Int
+	Price      *big.Rat
+	Profitable bool
+}
+
+// ProfitabilityPolicy decides whether commitments should be delivered now or deferred
+type ProfitabilityPolicy struct {
+	priceSource PriceSource
+	margin      *big.Rat
+	feeDecimals uint8
+}
+
+func NewProfitabilityPolicy(config *ProfitabilityConfig) (*ProfitabilityPolicy, error) {
+	// Without a configured price, fees are taken to be paid in ether
+	var priceSource PriceSource = IdentityPriceSource{}
+	feeDecimals := uint8(etherDecimals)
+	if config.PriceFile != "" {
+		priceSource = NewFilePriceSource(config.PriceFile)
+		feeDecimals = dotDecimals
+	} else if config.EthDotPrice != "" {
+		staticPriceSource, err := NewStaticPriceSource(config.EthDotPrice)
+		if err != nil {
+			return nil, err
+		}
+		priceSource = staticPriceSource
+		feeDecimals = dotDecimals
+	}
+	if config.FeeDecimals != nil {
+		feeDecimals = *config.FeeDecimals
+	}
+
+	margin := big.NewRat(1, 1)
+	if config.Margin != 0 {
+		if config.Margin < 0 {
+			return nil, retry.Fatal(fmt.Errorf("invalid profitability margin %v", config.Margin))
+		}
+		margin = new(big.Rat).SetFloat64(config.Margin)
+	}
+
+	return &ProfitabilityPolicy{
+		priceSource: priceSource,
+		margin:      margin,
+		feeDecimals: feeDecimals,
+	}, nil
+}
+
+// Check compares the total fee of a commitment with the cost of delivering it, given the estimated gas
+// and the gas price in wei. The commitment is profitable if its fee is at least the cost times the margin.
+func (p *ProfitabilityPolicy) Check(fee *big.Int, gas uint64, gasPrice *big.Int) (*ProfitabilityDecision, error) {
+	price, err := p.priceSource.EthPrice()
+	if err != nil {
+		return nil, err
+	}
+
+	// cost = gas * gasPrice / 10^18 ETH * price * 10^feeDecimals
+	costWei := new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+	cost := new(big.Rat).SetInt(costWei)
+	cost.Mul(cost, price)
+	cost.Mul(cost, new(big.Rat).SetFrac(pow10(int64(p.feeDecimals)), pow10(etherDecimals)))
+
+	minimumFee := new(big.Rat).Mul(cost, p.margin)
+
+	return &ProfitabilityDecision{
+		Fee:        fee,
+		Cost:       ceil(cost),
+		MinimumFee: ceil(minimumFee),
+		Price:      price,
+		Profitable: new(big.Rat).SetInt(fee).Cmp(minimumFee) >= 0,
+	}, nil
+}
+
+func pow10(exp int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil)
+}
+
+func ceil(value *big.Rat) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Sign() > 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
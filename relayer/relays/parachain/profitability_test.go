@@ -0,0 +1,125 @@
+package parachain
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticPriceSource(t *testing.T) {
+	source, err := NewStaticPriceSource(" 150.5 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	price, err := source.EthPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(301, 2), price)
+
+	_, err = NewStaticPriceSource("")
+	assert.Error(t, err)
+
+	_, err = NewStaticPriceSource("-1")
+	assert.Error(t, err)
+}
+
+func TestFilePriceSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "price")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "eth-dot")
+	source := NewFilePriceSource(path)
+
+	_, err = source.EthPrice()
+	assert.Error(t, err)
+
+	err = ioutil.WriteFile(path, []byte("200\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err := source.EthPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(200, 1), price)
+
+	// Updates to the file are picked up
+	err = ioutil.WriteFile(path, []byte("250"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err = source.EthPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(250, 1), price)
+}
+
+func TestProfitabilityPolicy(t *testing.T) {
+	policy, err := NewProfitabilityPolicy(&ProfitabilityConfig{
+		Enabled: true,
+		Margin:  1.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 100000 gas at 10 gwei costs 0.001 ETH. Without a price, fees are paid in wei.
+	gasPrice := big.NewInt(10_000_000_000)
+	cost := big.NewInt(1_000_000_000_000_000)
+	minimumFee := big.NewInt(1_500_000_000_000_000)
+
+	decision, err := policy.Check(minimumFee, 100000, gasPrice)
+	assert.NoError(t, err)
+	assert.Equal(t, cost, decision.Cost)
+	assert.Equal(t, minimumFee, decision.MinimumFee)
+	assert.True(t, decision.Profitable)
+
+	decision, err = policy.Check(new(big.Int).Sub(minimumFee, big.NewInt(1)), 100000, gasPrice)
+	assert.NoError(t, err)
+	assert.False(t, decision.Profitable)
+}
+
+func TestProfitabilityPolicyWithPrice(t *testing.T) {
+	policy, err := NewProfitabilityPolicy(&ProfitabilityConfig{
+		Enabled:     true,
+		Margin:      1.5,
+		EthDotPrice: "100",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 100000 gas at 10 gwei costs 0.001 ETH, or 0.1 DOT
+	gasPrice := big.NewInt(10_000_000_000)
+	cost := big.NewInt(1_000_000_000)
+	minimumFee := big.NewInt(1_500_000_000)
+
+	decision, err := policy.Check(minimumFee, 100000, gasPrice)
+	assert.NoError(t, err)
+	assert.Equal(t, cost, decision.Cost)
+	assert.Equal(t, minimumFee, decision.MinimumFee)
+	assert.True(t, decision.Profitable)
+
+	decision, err = policy.Check(new(big.Int).Sub(minimumFee, big.NewInt(1)), 100000, gasPrice)
+	assert.NoError(t, err)
+	assert.False(t, decision.Profitable)
+}
+
+func TestProfitabilityPolicyDefaultMargin(t *testing.T) {
+	policy, err := NewProfitabilityPolicy(&ProfitabilityConfig{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decision, err := policy.Check(big.NewInt(21000), 21000, big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(21000), decision.MinimumFee)
+	assert.True(t, decision.Profitable)
+
+	_, err = NewProfitabilityPolicy(&ProfitabilityConfig{Enabled: true, Margin: -1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,421 @@
+package store
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jinzhu/gorm"
+	_ "github.com/mattn/go-sqlite3" // required by gorm
+)
+
+// CommitmentRange records the range of message nonces in a commitment
+// included in the digest of a parachain block
+type CommitmentRange struct {
+	ParaBlock  uint64 `gorm:"primary_key;auto_increment:false"`
+	Channel    string `gorm:"primary_key"`
+	FirstNonce uint64
+	LastNonce  uint64
+}
+
+func (CommitmentRange) TableName() string {
+	return "commitment_ranges"
+}
+
+// IndexedRange is the range of parachain blocks for which the commitments
+// of every block have been recorded
+type IndexedRange struct {
+	ID        uint `gorm:"primary_key"`
+	FromBlock uint64
+	ToBlock   uint64
+}
+
+func (IndexedRange) TableName() string {
+	return "indexed_ranges"
+}
+
+// ParaHeadInclusion records the earliest known relay chain block at which a
+// parachain block was the para head of our parachain
+type ParaHeadInclusion struct {
+	ParaBlock  uint64 `gorm:"primary_key;auto_increment:false"`
+	RelayBlock uint64
+}
+
+func (ParaHeadInclusion) TableName() string {
+	return "para_head_inclusions"
+}
+
+// RelayIndexState is the last relay chain block processed by the inclusion index
+type RelayIndexState struct {
+	ID             uint `gorm:"primary_key"`
+	LastRelayBlock uint64
+}
+
+func (RelayIndexState) TableName() string {
+	return "relay_index_state"
+}
+
+// DeliveryTrackerState is the last Ethereum block processed by the delivery tracker
+type DeliveryTrackerState struct {
+	ID                uint `gorm:"primary_key"`
+	LastEthereumBlock uint64
+}
+
+func (DeliveryTrackerState) TableName() string {
+	return "delivery_tracker_state"
+}
+
+// MessageDelivery records the delivery of a message to its inbound channel on Ethereum
+type MessageDelivery struct {
+	Channel        string `gorm:"primary_key"`
+	Nonce          uint64 `gorm:"primary_key;auto_increment:false"`
+	SubmitTxHash   common.Hash
+	Dispatched     bool
+	Result         bool
+	DispatchTxHash common.Hash
+	DispatchBlock  uint64
+	UpdatedAt      time.Time
+}
+
+func (MessageDelivery) TableName() string {
+	return "message_deliveries"
+}
+
+// FailedMessagePackage records the failures of a message package which could not be delivered.
+// Once its attempts are used up, the package is dead-lettered and skipped until it is replayed.
+type FailedMessagePackage struct {
+	ID             uint        `gorm:"primary_key"`
+	Channel        string      `gorm:"unique_index:idx_failed_message_package"`
+	CommitmentHash common.Hash `gorm:"unique_index:idx_failed_message_package"`
+	ParaBlock      uint64
+	CommitmentData []byte
+	Attempts       uint64
+	// Error returned by the last attempt
+	Reason       string
+	DeadLettered bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (FailedMessagePackage) TableName() string {
+	return "failed_message_packages"
+}
+
+// Database is an index of the commitments found on the parachain, and of the relay chain
+// blocks which included parachain blocks. It allows searches for lost commitments and their
+// proofs to skip blocks which have already been scanned.
+type Database struct {
+	Path string
+	DB   *gorm.DB
+}
+
+// NewDatabase creates an index backed by the sqlite file at path. If path is
+// empty, the index is kept in memory and lost on shutdown.
+func NewDatabase(path string) *Database {
+	return &Database{
+		Path: path,
+		DB:   nil,
+	}
+}
+
+func (d *Database) Initialize() error {
+	path := d.Path
+	if path == "" {
+		path = ":memory:"
+	}
+
+	db, err := gorm.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+
+	// Each connection to an in-memory database would see a separate database
+	db.DB().SetMaxOpenConns(1)
+
+	err = db.AutoMigrate(&CommitmentRange{}, &IndexedRange{}, &ParaHeadInclusion{}, &RelayIndexState{}, &DeliveryTrackerState{}, &MessageDelivery{}, &FailedMessagePackage{}).Error
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	d.DB = db
+
+	return nil
+}
+
+func (d *Database) Close() error {
+	if d.DB == nil {
+		return nil
+	}
+	return d.DB.Close()
+}
+
+func (d *Database) PutCommitmentRange(commitmentRange CommitmentRange) error {
+	return d.DB.
+		Where(CommitmentRange{ParaBlock: commitmentRange.ParaBlock, Channel: commitmentRange.Channel}).
+		Assign(CommitmentRange{FirstNonce: commitmentRange.FirstNonce, LastNonce: commitmentRange.LastNonce}).
+		FirstOrCreate(&commitmentRange).Error
+}
+
+// GetCommitmentRanges returns the commitments recorded for blocks between fromBlock and toBlock
+// inclusive, in descending block order
+func (d *Database) GetCommitmentRanges(fromBlock, toBlock uint64) ([]CommitmentRange, error) {
+	var ranges []CommitmentRange
+	err := d.DB.
+		Where("para_block >= ? AND para_block <= ?", fromBlock, toBlock).
+		Order("para_block desc").
+		Find(&ranges).Error
+	if err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// GetIndexedRange returns the range of blocks which has been fully indexed, or nil if
+// no blocks have been indexed
+func (d *Database) GetIndexedRange() (*IndexedRange, error) {
+	var indexedRange IndexedRange
+	err := d.DB.First(&indexedRange).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &indexedRange, nil
+}
+
+// SetIndexedRange records that all blocks between fromBlock and toBlock inclusive have been indexed.
+// The range is merged with the existing range if they overlap, otherwise it replaces it.
+func (d *Database) SetIndexedRange(fromBlock, toBlock uint64) error {
+	indexedRange, err := d.GetIndexedRange()
+	if err != nil {
+		return err
+	}
+
+	if indexedRange == nil {
+		indexedRange = &IndexedRange{}
+	} else if fromBlock <= indexedRange.ToBlock+1 && indexedRange.FromBlock <= toBlock+1 {
+		if indexedRange.FromBlock < fromBlock {
+			fromBlock = indexedRange.FromBlock
+		}
+		if indexedRange.ToBlock > toBlock {
+			toBlock = indexedRange.ToBlock
+		}
+	}
+
+	indexedRange.FromBlock = fromBlock
+	indexedRange.ToBlock = toBlock
+
+	return d.DB.Save(indexedRange).Error
+}
+
+// PutParaHeadInclusion records that paraBlock was the para head at relayBlock. Only the
+// earliest relay chain block is kept for each parachain block.
+func (d *Database) PutParaHeadInclusion(paraBlock, relayBlock uint64) error {
+	relayBlockNumber, ok, err := d.GetParaHeadInclusion(paraBlock)
+	if err != nil {
+		return err
+	}
+	if ok && relayBlockNumber <= relayBlock {
+		return nil
+	}
+
+	return d.DB.Save(&ParaHeadInclusion{
+		ParaBlock:  paraBlock,
+		RelayBlock: relayBlock,
+	}).Error
+}
+
+// GetParaHeadInclusion returns the earliest known relay chain block at which paraBlock was the para head
+func (d *Database) GetParaHeadInclusion(paraBlock uint64) (uint64, bool, error) {
+	var inclusion ParaHeadInclusion
+	err := d.DB.Where("para_block = ?", paraBlock).First(&inclusion).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return inclusion.RelayBlock, true, nil
+}
+
+// GetLastIndexedRelayBlock returns the last relay chain block processed by the inclusion index
+func (d *Database) GetLastIndexedRelayBlock() (uint64, bool, error) {
+	var state RelayIndexState
+	err := d.DB.First(&state).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return state.LastRelayBlock, true, nil
+}
+
+func (d *Database) SetLastIndexedRelayBlock(relayBlock uint64) error {
+	var state RelayIndexState
+	err := d.DB.First(&state).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return err
+	}
+
+	state.LastRelayBlock = relayBlock
+
+	return d.DB.Save(&state).Error
+}
+
+// GetLastTrackedEthereumBlock returns the last Ethereum block processed by the delivery tracker
+func (d *Database) GetLastTrackedEthereumBlock() (uint64, bool, error) {
+	var state DeliveryTrackerState
+	err := d.DB.First(&state).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return state.LastEthereumBlock, true, nil
+}
+
+func (d *Database) SetLastTrackedEthereumBlock(blockNumber uint64) error {
+	var state DeliveryTrackerState
+	err := d.DB.First(&state).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return err
+	}
+
+	state.LastEthereumBlock = blockNumber
+
+	return d.DB.Save(&state).Error
+}
+
+// PutSubmittedMessage records that a transaction delivering the message was sent
+func (d *Database) PutSubmittedMessage(channel string, nonce uint64, txHash common.Hash) error {
+	delivery := MessageDelivery{}
+	return d.DB.
+		Where(MessageDelivery{Channel: channel, Nonce: nonce}).
+		Assign(MessageDelivery{SubmitTxHash: txHash}).
+		FirstOrCreate(&delivery).Error
+}
+
+// PutDispatchedMessage records the outcome of dispatching the message on Ethereum
+func (d *Database) PutDispatchedMessage(channel string, nonce uint64, result bool, txHash common.Hash, blockNumber uint64) error {
+	delivery := MessageDelivery{}
+	return d.DB.
+		Where(MessageDelivery{Channel: channel, Nonce: nonce}).
+		Assign(map[string]interface{}{
+			"dispatched":       true,
+			"result":           result,
+			"dispatch_tx_hash": txHash,
+			"dispatch_block":   blockNumber,
+		}).
+		FirstOrCreate(&delivery).Error
+}
+
+// GetMessageDelivery returns the delivery of the given message, or nil if it is unknown
+func (d *Database) GetMessageDelivery(channel string, nonce uint64) (*MessageDelivery, error) {
+	var delivery MessageDelivery
+	err := d.DB.Where("channel = ? AND nonce = ?", channel, nonce).First(&delivery).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// RecordMessagePackageFailure counts a failed attempt to deliver the message package. The package is
+// dead-lettered once maxAttempts attempts have failed.
+func (d *Database) RecordMessagePackageFailure(
+	channel string,
+	commitmentHash common.Hash,
+	paraBlock uint64,
+	commitmentData []byte,
+	reason string,
+	maxAttempts uint64,
+) (*FailedMessagePackage, error) {
+	var failure FailedMessagePackage
+	err := d.DB.
+		Where(FailedMessagePackage{Channel: channel, CommitmentHash: commitmentHash}).
+		FirstOrInit(&failure).Error
+	if err != nil {
+		return nil, err
+	}
+
+	failure.ParaBlock = paraBlock
+	failure.CommitmentData = commitmentData
+	failure.Attempts++
+	failure.Reason = reason
+	failure.DeadLettered = failure.Attempts >= maxAttempts
+
+	err = d.DB.Save(&failure).Error
+	if err != nil {
+		return nil, err
+	}
+	return &failure, nil
+}
+
+// ClearMessagePackageFailures forgets the failures of a message package which has been delivered
+func (d *Database) ClearMessagePackageFailures(channel string, commitmentHash common.Hash) error {
+	return d.DB.
+		Where("channel = ? AND commitment_hash = ?", channel, commitmentHash).
+		Delete(FailedMessagePackage{}).Error
+}
+
+// IsDeadLettered reports whether the message package has been dead-lettered
+func (d *Database) IsDeadLettered(channel string, commitmentHash common.Hash) (bool, error) {
+	var count int
+	err := d.DB.Model(&FailedMessagePackage{}).
+		Where("channel = ? AND commitment_hash = ? AND dead_lettered = ?", channel, commitmentHash, true).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsChannelPaused reports whether any message package on the channel has been dead-lettered. Later
+// packages on the channel can't be delivered until it is, since nonces are sequential.
+func (d *Database) IsChannelPaused(channel string) (bool, error) {
+	var count int
+	err := d.DB.Model(&FailedMessagePackage{}).
+		Where("channel = ? AND dead_lettered = ?", channel, true).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetFailedMessagePackages returns every message package which has failed, dead-lettered or not
+func (d *Database) GetFailedMessagePackages() ([]FailedMessagePackage, error) {
+	var failures []FailedMessagePackage
+	err := d.DB.Order("id").Find(&failures).Error
+	if err != nil {
+		return nil, err
+	}
+	return failures, nil
+}
+
+func (d *Database) GetFailedMessagePackage(id uint) (*FailedMessagePackage, error) {
+	var failure FailedMessagePackage
+	err := d.DB.First(&failure, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &failure, nil
+}
+
+// ReplayFailedMessagePackage resets the attempts of the message package, so that it is delivered again
+// when the relay next finds it undelivered
+func (d *Database) ReplayFailedMessagePackage(failure *FailedMessagePackage) error {
+	return d.DB.Model(failure).Updates(map[string]interface{}{
+		"attempts":      0,
+		"dead_lettered": false,
+	}).Error
+}
+
+func (d *Database) DeleteFailedMessagePackage(failure *FailedMessagePackage) error {
+	return d.DB.Delete(failure).Error
+}
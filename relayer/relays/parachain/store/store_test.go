@@ -0,0 +1,213 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/snowfork/snowbridge/relayer/relays/parachain/store"
+	"github.com/stretchr/testify/suite"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+
+	database *store.Database
+}
+
+func TestStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
+
+func (suite *StoreTestSuite) SetupTest() {
+	database := store.NewDatabase("")
+
+	err := database.Initialize()
+	if err != nil {
+		suite.Fail(err.Error())
+	}
+
+	suite.database = database
+}
+
+func (suite *StoreTestSuite) TearDownTest() {
+	suite.database.Close()
+}
+
+func (suite *StoreTestSuite) TestCommitmentRanges() {
+	ranges := []store.CommitmentRange{
+		{ParaBlock: 10, Channel: "basic", FirstNonce: 1, LastNonce: 2},
+		{ParaBlock: 12, Channel: "basic", FirstNonce: 3, LastNonce: 3},
+		{ParaBlock: 12, Channel: "incentivized", FirstNonce: 1, LastNonce: 4},
+		{ParaBlock: 20, Channel: "basic", FirstNonce: 4, LastNonce: 5},
+	}
+	for _, r := range ranges {
+		suite.Nil(suite.database.PutCommitmentRange(r))
+	}
+
+	// Recording a commitment twice updates the existing entry
+	suite.Nil(suite.database.PutCommitmentRange(store.CommitmentRange{
+		ParaBlock: 12, Channel: "basic", FirstNonce: 3, LastNonce: 3,
+	}))
+
+	found, err := suite.database.GetCommitmentRanges(11, 20)
+	suite.Nil(err)
+	suite.Equal(3, len(found))
+	suite.Equal(uint64(20), found[0].ParaBlock)
+	suite.Equal(uint64(12), found[1].ParaBlock)
+	suite.Equal(uint64(12), found[2].ParaBlock)
+}
+
+func (suite *StoreTestSuite) TestIndexedRange() {
+	indexedRange, err := suite.database.GetIndexedRange()
+	suite.Nil(err)
+	suite.Nil(indexedRange)
+
+	suite.Nil(suite.database.SetIndexedRange(10, 20))
+
+	// Adjacent ranges are merged
+	suite.Nil(suite.database.SetIndexedRange(21, 30))
+	indexedRange, err = suite.database.GetIndexedRange()
+	suite.Nil(err)
+	suite.Equal(uint64(10), indexedRange.FromBlock)
+	suite.Equal(uint64(30), indexedRange.ToBlock)
+
+	// Disjoint ranges replace the existing range
+	suite.Nil(suite.database.SetIndexedRange(40, 50))
+	indexedRange, err = suite.database.GetIndexedRange()
+	suite.Nil(err)
+	suite.Equal(uint64(40), indexedRange.FromBlock)
+	suite.Equal(uint64(50), indexedRange.ToBlock)
+}
+
+func (suite *StoreTestSuite) TestParaHeadInclusion() {
+	_, ok, err := suite.database.GetParaHeadInclusion(5)
+	suite.Nil(err)
+	suite.False(ok)
+
+	suite.Nil(suite.database.PutParaHeadInclusion(5, 102))
+
+	// Only the earliest relay chain block is kept
+	suite.Nil(suite.database.PutParaHeadInclusion(5, 103))
+	suite.Nil(suite.database.PutParaHeadInclusion(5, 101))
+
+	relayBlock, ok, err := suite.database.GetParaHeadInclusion(5)
+	suite.Nil(err)
+	suite.True(ok)
+	suite.Equal(uint64(101), relayBlock)
+
+	_, ok, err = suite.database.GetLastIndexedRelayBlock()
+	suite.Nil(err)
+	suite.False(ok)
+
+	suite.Nil(suite.database.SetLastIndexedRelayBlock(103))
+	suite.Nil(suite.database.SetLastIndexedRelayBlock(104))
+
+	lastRelayBlock, ok, err := suite.database.GetLastIndexedRelayBlock()
+	suite.Nil(err)
+	suite.True(ok)
+	suite.Equal(uint64(104), lastRelayBlock)
+}
+
+func (suite *StoreTestSuite) TestLastTrackedEthereumBlock() {
+	_, ok, err := suite.database.GetLastTrackedEthereumBlock()
+	suite.Nil(err)
+	suite.False(ok)
+
+	suite.Nil(suite.database.SetLastTrackedEthereumBlock(200))
+	suite.Nil(suite.database.SetLastTrackedEthereumBlock(201))
+
+	blockNumber, ok, err := suite.database.GetLastTrackedEthereumBlock()
+	suite.Nil(err)
+	suite.True(ok)
+	suite.Equal(uint64(201), blockNumber)
+}
+
+func (suite *StoreTestSuite) TestMessageDelivery() {
+	submitTxHash := common.HexToHash("0x01")
+	dispatchTxHash := common.HexToHash("0x02")
+
+	delivery, err := suite.database.GetMessageDelivery("basic", 7)
+	suite.Nil(err)
+	suite.Nil(delivery)
+
+	suite.Nil(suite.database.PutSubmittedMessage("basic", 7, submitTxHash))
+	delivery, err = suite.database.GetMessageDelivery("basic", 7)
+	suite.Nil(err)
+	suite.Equal(submitTxHash, delivery.SubmitTxHash)
+	suite.False(delivery.Dispatched)
+
+	suite.Nil(suite.database.PutDispatchedMessage("basic", 7, false, dispatchTxHash, 100))
+	delivery, err = suite.database.GetMessageDelivery("basic", 7)
+	suite.Nil(err)
+	suite.Equal(submitTxHash, delivery.SubmitTxHash)
+	suite.True(delivery.Dispatched)
+	suite.False(delivery.Result)
+	suite.Equal(dispatchTxHash, delivery.DispatchTxHash)
+	suite.Equal(uint64(100), delivery.DispatchBlock)
+
+	// Messages delivered by other relayers are recorded too
+	suite.Nil(suite.database.PutDispatchedMessage("incentivized", 7, true, dispatchTxHash, 101))
+	delivery, err = suite.database.GetMessageDelivery("incentivized", 7)
+	suite.Nil(err)
+	suite.True(delivery.Result)
+	suite.Equal(common.Hash{}, delivery.SubmitTxHash)
+}
+
+func (suite *StoreTestSuite) TestFailedMessagePackages() {
+	commitmentHash := common.HexToHash("0x03")
+	data := []byte{1, 2, 3}
+
+	for attempt := uint64(1); attempt <= 3; attempt++ {
+		failure, err := suite.database.RecordMessagePackageFailure("basic", commitmentHash, 10, data, "execution reverted", 3)
+		suite.Nil(err)
+		suite.Equal(attempt, failure.Attempts)
+		suite.Equal(attempt == 3, failure.DeadLettered)
+	}
+
+	deadLettered, err := suite.database.IsDeadLettered("basic", commitmentHash)
+	suite.Nil(err)
+	suite.True(deadLettered)
+
+	// Packages are told apart by channel
+	deadLettered, err = suite.database.IsDeadLettered("incentivized", commitmentHash)
+	suite.Nil(err)
+	suite.False(deadLettered)
+
+	paused, err := suite.database.IsChannelPaused("basic")
+	suite.Nil(err)
+	suite.True(paused)
+
+	paused, err = suite.database.IsChannelPaused("incentivized")
+	suite.Nil(err)
+	suite.False(paused)
+
+	failures, err := suite.database.GetFailedMessagePackages()
+	suite.Nil(err)
+	suite.Equal(1, len(failures))
+	suite.Equal(uint64(10), failures[0].ParaBlock)
+	suite.Equal(data, failures[0].CommitmentData)
+	suite.Equal("execution reverted", failures[0].Reason)
+
+	failure, err := suite.database.GetFailedMessagePackage(failures[0].ID)
+	suite.Nil(err)
+	suite.Nil(suite.database.ReplayFailedMessagePackage(failure))
+
+	deadLettered, err = suite.database.IsDeadLettered("basic", commitmentHash)
+	suite.Nil(err)
+	suite.False(deadLettered)
+
+	paused, err = suite.database.IsChannelPaused("basic")
+	suite.Nil(err)
+	suite.False(paused)
+
+	// A replayed package gets its attempts back
+	failure, err = suite.database.RecordMessagePackageFailure("basic", commitmentHash, 10, data, "execution reverted", 3)
+	suite.Nil(err)
+	suite.Equal(uint64(1), failure.Attempts)
+	suite.False(failure.DeadLettered)
+
+	suite.Nil(suite.database.ClearMessagePackageFailures("basic", commitmentHash))
+	failures, err = suite.database.GetFailedMessagePackages()
+	suite.Nil(err)
+	suite.Equal(0, len(failures))
+}
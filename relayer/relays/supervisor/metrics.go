@@ -0,0 +1,25 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package supervisor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	relayUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "snowbridge",
+		Subsystem: "supervisor",
+		Name:      "relay_up",
+		Help:      "Whether the relay is running, by relay.",
+	}, []string{"relay"})
+
+	relayRestarts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "supervisor",
+		Name:      "relay_restarts_total",
+		Help:      "Number of times the relay was restarted after failing, by relay.",
+	}, []string{"relay"})
+)
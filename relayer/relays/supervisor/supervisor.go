@@ -0,0 +1,145 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package supervisor runs several relays in one process, restarting each relay on its own when it
+// fails so that the other relays keep running. A fatal error stops every relay.
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/snowfork/snowbridge/relayer/health"
+	"github.com/snowfork/snowbridge/relayer/retry"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Delay before the first restart of a failed relay. It doubles with each consecutive failure.
+	minRestartDelay = 5 * time.Second
+	maxRestartDelay = 5 * time.Minute
+)
+
+// Relay is implemented by each of the relays
+type Relay interface {
+	Start(ctx context.Context, eg *errgroup.Group) error
+}
+
+// Factory creates a relay. A fresh relay is created for every restart, so that no state is carried
+// over from the instance which failed.
+type Factory func() (Relay, error)
+
+type supervised struct {
+	name    string
+	create  Factory
+	started *health.Condition
+}
+
+type Supervisor struct {
+	relays          []*supervised
+	minRestartDelay time.Duration
+	maxRestartDelay time.Duration
+}
+
+func New() *Supervisor {
+	return &Supervisor{
+		minRestartDelay: minRestartDelay,
+		maxRestartDelay: maxRestartDelay,
+	}
+}
+
+// Add registers a relay to be run. The supervisor isn't ready until every relay has started once.
+func (s *Supervisor) Add(name string, create Factory) {
+	s.relays = append(s.relays, &supervised{
+		name:    name,
+		create:  create,
+		started: health.Expect(name + " relay started"),
+	})
+}
+
+// Run runs every relay until the context is cancelled or a relay fails with a fatal error, which
+// stops the other relays and is returned
+func (s *Supervisor) Run(ctx context.Context) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, relay := range s.relays {
+		relay := relay
+		eg.Go(func() error {
+			return s.supervise(ctx, relay)
+		})
+	}
+	return eg.Wait()
+}
+
+func (s *Supervisor) supervise(ctx context.Context, relay *supervised) error {
+	logger := log.WithField("relay", relay.name)
+	delay := s.minRestartDelay
+
+	for {
+		startedAt := time.Now()
+		err := s.runOnce(ctx, relay)
+		relayUp.WithLabelValues(relay.name).Set(0)
+
+		if ctx.Err() != nil {
+			logger.Info("Relay stopped")
+			return nil
+		}
+
+		if retry.IsFatal(err) {
+			logger.WithError(err).Error("Relay failed with a fatal error")
+			return fmt.Errorf("%s relay: %w", relay.name, err)
+		}
+
+		// A relay which ran for a while before failing is restarted promptly
+		if time.Since(startedAt) > s.maxRestartDelay {
+			delay = s.minRestartDelay
+		}
+
+		logger.WithError(err).WithField("restartIn", delay.String()).Error("Relay failed")
+		relayRestarts.WithLabelValues(relay.name).Inc()
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Relay stopped")
+			return nil
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > s.maxRestartDelay {
+			delay = s.maxRestartDelay
+		}
+	}
+}
+
+// Creates and starts a relay, then waits for it to stop. Everything the relay started is stopped
+// before returning, even if it failed while starting.
+func (s *Supervisor) runOnce(ctx context.Context, relay *supervised) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	instance, err := relay.create()
+	if err != nil {
+		return fmt.Errorf("create relay: %w", err)
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	err = instance.Start(ctx, eg)
+	if err != nil {
+		cancel()
+		eg.Wait()
+		return fmt.Errorf("start relay: %w", err)
+	}
+	relay.started.Satisfy()
+	relayUp.WithLabelValues(relay.name).Set(1)
+	log.WithField("relay", relay.name).Info("Relay started")
+
+	err = eg.Wait()
+	if err == nil {
+		err = fmt.Errorf("relay stopped unexpectedly")
+	}
+	return err
+}
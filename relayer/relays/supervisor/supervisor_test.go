@@ -0,0 +1,147 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/snowfork/snowbridge/relayer/retry"
+)
+
+type testRelay struct {
+	start func(ctx context.Context, eg *errgroup.Group) error
+}
+
+func (r *testRelay) Start(ctx context.Context, eg *errgroup.Group) error {
+	return r.start(ctx, eg)
+}
+
+func newTestSupervisor() *Supervisor {
+	s := New()
+	s.minRestartDelay = time.Millisecond
+	s.maxRestartDelay = 10 * time.Millisecond
+	return s
+}
+
+func TestFailingRelayIsRestartedAlone(t *testing.T) {
+	s := newTestSupervisor()
+
+	var failingStarts, healthyStarts int32
+	healthyStopped := make(chan struct{})
+
+	s.Add("failing", func() (Relay, error) {
+		return &testRelay{start: func(ctx context.Context, eg *errgroup.Group) error {
+			n := atomic.AddInt32(&failingStarts, 1)
+			if n == 1 {
+				return fmt.Errorf("failed to connect")
+			}
+			eg.Go(func() error {
+				return fmt.Errorf("pipeline failed")
+			})
+			return nil
+		}}, nil
+	})
+	s.Add("healthy", func() (Relay, error) {
+		return &testRelay{start: func(ctx context.Context, eg *errgroup.Group) error {
+			atomic.AddInt32(&healthyStarts, 1)
+			eg.Go(func() error {
+				<-ctx.Done()
+				close(healthyStopped)
+				return ctx.Err()
+			})
+			return nil
+		}}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Run(ctx))
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&failingStarts) >= 3
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&healthyStarts))
+
+	cancel()
+	<-done
+	<-healthyStopped
+}
+
+func TestRelayIsRecreatedAfterCreationFails(t *testing.T) {
+	s := newTestSupervisor()
+
+	var creations int32
+	started := make(chan struct{})
+
+	s.Add("relay", func() (Relay, error) {
+		if atomic.AddInt32(&creations, 1) < 3 {
+			return nil, fmt.Errorf("invalid config")
+		}
+		return &testRelay{start: func(ctx context.Context, eg *errgroup.Group) error {
+			close(started)
+			eg.Go(func() error {
+				<-ctx.Done()
+				return nil
+			})
+			return nil
+		}}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Run(ctx))
+		close(done)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("relay was not started")
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&creations))
+
+	cancel()
+	<-done
+}
+
+func TestFatalErrorStopsEveryRelay(t *testing.T) {
+	s := newTestSupervisor()
+
+	var fatalStarts int32
+	otherStopped := make(chan struct{})
+
+	s.Add("fatal", func() (Relay, error) {
+		return &testRelay{start: func(ctx context.Context, eg *errgroup.Group) error {
+			atomic.AddInt32(&fatalStarts, 1)
+			eg.Go(func() error {
+				return retry.Fatal(fmt.Errorf("invalid proof"))
+			})
+			return nil
+		}}, nil
+	})
+	s.Add("other", func() (Relay, error) {
+		return &testRelay{start: func(ctx context.Context, eg *errgroup.Group) error {
+			eg.Go(func() error {
+				<-ctx.Done()
+				close(otherStopped)
+				return ctx.Err()
+			})
+			return nil
+		}}, nil
+	})
+
+	err := s.Run(context.Background())
+	assert.True(t, retry.IsFatal(err))
+	assert.EqualError(t, err, "fatal relay: invalid proof")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fatalStarts))
+	<-otherStopped
+}
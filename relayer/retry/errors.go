@@ -0,0 +1,152 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FatalError is an error which neither retrying nor restarting the relay can fix, such as bad
+// configuration, an invalid proof or a problem with a key
+type FatalError struct {
+	Err error
+}
+
+func (e *FatalError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FatalError) Unwrap() error {
+	return e.Err
+}
+
+// TransientError is an error which is expected to go away when the request is retried, such as a
+// dropped connection or an overloaded node
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// Fatal marks the error as fatal
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &FatalError{Err: err}
+}
+
+// Transient marks the error as transient
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TransientError{Err: err}
+}
+
+// IsFatal reports whether the error, or any error it wraps, was marked as fatal
+func IsFatal(err error) bool {
+	var fatal *FatalError
+	return errors.As(err, &fatal)
+}
+
+// JSON-RPC error codes which nodes return when they are temporarily unable to serve a request
+const (
+	codeInternalError = -32603
+	codeLimitExceeded = -32005
+)
+
+// Messages of errors returned by Ethereum nodes which haven't caught up with the block requested
+var laggingNodeMessages = []string{
+	"header not found",
+	"missing trie node",
+}
+
+// Messages of errors returned by Ethereum nodes for calls which revert
+var revertMessages = []string{
+	"execution reverted",
+	"VM Exception while processing transaction",
+}
+
+// isRevert reports whether the error is returned for an Ethereum call which reverted, going by its
+// revert data or its message
+func isRevert(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) && dataErr.ErrorData() != nil {
+		return true
+	}
+
+	message := err.Error()
+	for _, revert := range revertMessages {
+		if strings.Contains(message, revert) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTransient reports whether the error was marked as transient, or is a network or node error
+// which is likely to go away on retry. Errors marked as fatal are never transient.
+func IsTransient(err error) bool {
+	if err == nil || IsFatal(err) {
+		return false
+	}
+
+	var transient *TransientError
+	if errors.As(err, &transient) {
+		return true
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Implemented by the errors of both the Ethereum and Substrate RPC clients
+	var rpcErr interface{ ErrorCode() int }
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		case codeInternalError, codeLimitExceeded:
+			// Some nodes return an internal error for calls which revert, and those revert again on retry
+			return !isRevert(err)
+		}
+	}
+
+	// Returned by the Ethereum RPC client over HTTP for responses with an error status
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+
+	message := err.Error()
+	for _, lagging := range laggingNodeMessages {
+		if strings.Contains(message, lagging) {
+			return true
+		}
+	}
+
+	return false
+}
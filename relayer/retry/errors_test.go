@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type codedError struct {
+	code int
+}
+
+func (e codedError) Error() string  { return fmt.Sprintf("rpc error %d", e.code) }
+func (e codedError) ErrorCode() int { return e.code }
+
+// revertError is returned by nodes which report reverted calls as internal errors
+type revertError struct {
+	message string
+	data    interface{}
+}
+
+func (e revertError) Error() string          { return e.message }
+func (e revertError) ErrorCode() int         { return codeInternalError }
+func (e revertError) ErrorData() interface{} { return e.data }
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"nil", nil, false},
+		{"marked transient", Transient(fmt.Errorf("busy")), true},
+		{"deadline exceeded", fmt.Errorf("fetch heads: %w", context.DeadlineExceeded), true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"internal error", codedError{codeInternalError}, true},
+		{"limit exceeded", codedError{codeLimitExceeded}, true},
+		{"invalid params", codedError{-32602}, false},
+		{"too many requests", rpc.HTTPError{StatusCode: 429}, true},
+		{"bad gateway", rpc.HTTPError{StatusCode: 502}, true},
+		{"unauthorized", rpc.HTTPError{StatusCode: 401}, false},
+		{"lagging node", fmt.Errorf("header not found"), true},
+		{"execution reverted", fmt.Errorf("execution reverted"), false},
+		{"internal error with revert data", revertError{"internal error", "0x08c379a0"}, false},
+		{"internal error for revert", revertError{"execution reverted: invalid nonce", nil}, false},
+		{"internal error for hardhat revert", revertError{"VM Exception while processing transaction: revert invalid nonce", nil}, false},
+		{"marked fatal", Fatal(fmt.Errorf("wrap: %w", io.EOF)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.transient, IsTransient(tt.err))
+		})
+	}
+}
+
+func TestIsFatal(t *testing.T) {
+	err := fmt.Errorf("start relay: %w", Fatal(fmt.Errorf("invalid proof")))
+	assert.True(t, IsFatal(err))
+	assert.EqualError(t, err, "start relay: invalid proof")
+
+	assert.False(t, IsFatal(fmt.Errorf("invalid proof")))
+	assert.False(t, IsFatal(nil))
+	assert.Nil(t, Fatal(nil))
+}
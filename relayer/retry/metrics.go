@@ -0,0 +1,34 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package retry
+
+import (
+	"net/url"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	rpcRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowbridge",
+		Subsystem: "rpc",
+		Name:      "retries_total",
+		Help:      "RPC requests to chain nodes which were retried after a transient error, by endpoint and method.",
+	}, []string{"endpoint", "method"})
+)
+
+func observeRetry(endpoint, method string) {
+	rpcRetries.WithLabelValues(EndpointLabel(endpoint), method).Inc()
+}
+
+// EndpointLabel returns the scheme and host of the endpoint URL, as used in metrics and logs. The path,
+// query and user info are left out, as hosted node providers put API keys in them.
+func EndpointLabel(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+// Package retry classifies errors as transient or fatal and retries requests which fail with
+// transient errors.
+//
+// Transient errors, such as a dropped connection or a node which is briefly overloaded, are retried
+// where the request is made, so that they don't stop the relay. Fatal errors, such as bad
+// configuration, an invalid proof or a problem with a key, can't be fixed by retrying and end the
+// process. Any other error restarts the relay.
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Policy retries a request with exponential backoff
+type Policy struct {
+	// Delay before the first retry. It doubles with each retry, up to MaxDelay.
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	// Number of times the request is made before giving up, including the first
+	MaxAttempts int
+	// Fraction by which each delay is randomly lengthened or shortened, so that relays sharing a
+	// node don't retry in lockstep
+	Jitter float64
+}
+
+// DefaultPolicy gives up after about a minute
+var DefaultPolicy = Policy{
+	InitialDelay: 500 * time.Millisecond,
+	MaxDelay:     30 * time.Second,
+	MaxAttempts:  8,
+	Jitter:       0.2,
+}
+
+// Do calls fn until it succeeds, fails with an error which isn't transient, the attempts are used
+// up or the context is cancelled. The last error is returned. If onRetry isn't nil, it is called
+// before waiting to retry.
+func (p Policy) Do(ctx context.Context, fn func() error, onRetry func(err error, delay time.Duration)) error {
+	delay := p.InitialDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !IsTransient(err) || attempt >= p.MaxAttempts {
+			return err
+		}
+
+		wait := p.jitter(delay)
+		if onRetry != nil {
+			onRetry(err, wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+}
+
+// DoRequest calls fn like Do, logging and counting each retry of the RPC request
+func (p Policy) DoRequest(ctx context.Context, endpoint, method string, fn func() error) error {
+	return p.Do(ctx, fn, func(err error, delay time.Duration) {
+		observeRetry(endpoint, method)
+		log.WithError(err).WithFields(log.Fields{
+			"endpoint": EndpointLabel(endpoint),
+			"method":   method,
+			"retryIn":  delay.String(),
+		}).Warn("Retrying request")
+	})
+}
+
+func (p Policy) jitter(delay time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return delay
+	}
+	factor := 1 + p.Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(delay) * factor)
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 Snowfork
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package retry
+
+import (
+	"context"
+
+	"github.com/snowfork/go-substrate-rpc-client/v3/client"
+)
+
+// Methods which must not be repeated, as the first request may have taken effect even though it failed
+var substrateSubmitMethods = map[string]bool{
+	"author_submitExtrinsic":         true,
+	"author_submitAndWatchExtrinsic": true,
+}
+
+// substrateClient retries the requests made through a substrate client which fail with transient errors.
+// Subscriptions aren't retried, as their callers already resubscribe when they fail.
+type substrateClient struct {
+	client.Client
+	policy Policy
+}
+
+// NewSubstrateClient wraps the client so that requests failing with transient errors are retried
+// according to the policy
+func NewSubstrateClient(cl client.Client, policy Policy) client.Client {
+	return &substrateClient{Client: cl, policy: policy}
+}
+
+func (c *substrateClient) Call(result interface{}, method string, args ...interface{}) error {
+	if substrateSubmitMethods[method] {
+		return c.Client.Call(result, method, args...)
+	}
+
+	return c.policy.DoRequest(context.Background(), c.URL(), method, func() error {
+		return c.Client.Call(result, method, args...)
+	})
+}
+
+// Close closes the wrapped client
+func (c *substrateClient) Close() {
+	if closer, ok := c.Client.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
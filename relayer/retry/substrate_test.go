@@ -0,0 +1,41 @@
+package retry
+
+import (
+	"io"
+	"testing"
+
+	"github.com/snowfork/go-substrate-rpc-client/v3/client"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingClient struct {
+	client.Client
+	calls int
+}
+
+func (c *failingClient) Call(result interface{}, method string, args ...interface{}) error {
+	c.calls++
+	return io.EOF
+}
+
+func (c *failingClient) URL() string {
+	return "ws://localhost:9944"
+}
+
+func TestSubstrateClientRetriesCalls(t *testing.T) {
+	inner := &failingClient{}
+	cl := NewSubstrateClient(inner, testPolicy)
+
+	err := cl.Call(nil, "chain_getBlockHash", 1)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, testPolicy.MaxAttempts, inner.calls)
+}
+
+func TestSubstrateClientSubmitsExtrinsicsOnce(t *testing.T) {
+	inner := &failingClient{}
+	cl := NewSubstrateClient(inner, testPolicy)
+
+	err := cl.Call(nil, "author_submitExtrinsic", "0x00")
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 1, inner.calls)
+}
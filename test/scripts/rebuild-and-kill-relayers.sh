@@ -24,11 +24,11 @@ jq \
     --arg k2 "$(address_for IncentivizedInboundChannel)" \
     --arg k3 "$(address_for BeefyLightClient)" \
 '
-    .source.contracts.BasicInboundChannel = $k1
-| .source.contracts.IncentivizedInboundChannel = $k2
+    .source.contracts.InboundChannels.basic = $k1
+| .source.contracts.InboundChannels.incentivized = $k2
 | .source.contracts.BeefyLightClient = $k3
-| .sink.contracts.BasicInboundChannel = $k1
-| .sink.contracts.IncentivizedInboundChannel = $k2
+| .sink.contracts.InboundChannels.basic = $k1
+| .sink.contracts.InboundChannels.incentivized = $k2
 ' \
 config/parachain-relay.json > $configdir/parachain-relay.json
 